@@ -0,0 +1,103 @@
+package przelewy24
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// AmountFromMajor converts an amount in major currency units (e.g. złoty or euro)
+// into minor units (e.g. grosze or cents), as expected by TransactionParams.Amount.
+func AmountFromMajor(major int) int {
+	return major * 100
+}
+
+// AmountFromDecimal parses a decimal amount in major currency units, such as
+// "12.34" or "12,34", into minor units, i.e. 1234. At most two fractional
+// digits are accepted. See AmountFromDecimalIn for a given currency.
+func AmountFromDecimal(s string) (int, error) {
+	return parseDecimalAmount(s, 2)
+}
+
+// AmountFromDecimalIn is like AmountFromDecimal, but accepts at most as many fractional
+// digits as currency has (see Currency.Decimals), so "12.345" is rejected for PLN
+// rather than rounded. Unsupported currencies are rejected.
+func AmountFromDecimalIn(s string, currency Currency) (int, error) {
+	if !currency.Valid() {
+		return 0, fmt.Errorf("currency %q is not supported", currency)
+	}
+
+	return parseDecimalAmount(s, currency.Decimals())
+}
+
+// parseDecimalAmount parses s into minor units, of which there are decimals digits.
+func parseDecimalAmount(s string, decimals int) (int, error) {
+	s = strings.TrimSpace(s)
+
+	whole, fraction, hasFraction := strings.Cut(strings.Replace(s, ",", ".", 1), ".")
+	if whole == "" || (hasFraction && fraction == "") || len(fraction) > decimals {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	for len(fraction) < decimals {
+		fraction += "0"
+	}
+
+	amount := 0
+	for _, c := range whole + fraction {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid amount %q", s)
+		}
+		if amount > (math.MaxInt-int(c-'0'))/10 {
+			return 0, fmt.Errorf("amount %q is too large", s)
+		}
+		amount = amount*10 + int(c-'0')
+	}
+
+	return amount, nil
+}
+
+type amountFormat struct {
+	symbol    string
+	prefix    bool
+	decimal   string
+	thousands string
+}
+
+var amountFormats = map[Currency]amountFormat{
+	CurrencyPLN: {symbol: "zł", decimal: ",", thousands: " "},
+	CurrencyEUR: {symbol: "€", decimal: ",", thousands: " "},
+	CurrencyCZK: {symbol: "Kč", decimal: ",", thousands: " "},
+	CurrencyGBP: {symbol: "£", prefix: true, decimal: ".", thousands: ","},
+}
+
+// FormatAmount renders an amount in minor units in the way customers are used to
+// for currency, e.g. 123456 PLN as "1 234,56 zł" and 123456 GBP as "£1,234.56".
+// Other currencies are rendered as "1234.56 XYZ".
+func FormatAmount(amount int, currency Currency) string {
+	format, ok := amountFormats[currency]
+	if !ok {
+		format = amountFormat{symbol: string(currency), decimal: "."}
+	}
+
+	// Negated as a uint, as -math.MinInt does not fit an int.
+	sign, magnitude := "", uint(amount)
+	if amount < 0 {
+		sign = "-"
+		magnitude = -magnitude
+	}
+
+	whole := fmt.Sprint(magnitude / 100)
+	if format.thousands != "" {
+		for i := len(whole) - 3; i > 0; i -= 3 {
+			whole = whole[:i] + format.thousands + whole[i:]
+		}
+	}
+	number := fmt.Sprintf("%s%s%02d", whole, format.decimal, magnitude%100)
+
+	if format.prefix {
+		return sign + format.symbol + number
+	}
+
+	return sign + number + " " + format.symbol
+}
@@ -0,0 +1,87 @@
+package przelewy24
+
+import (
+	"math"
+	"testing"
+)
+
+type decimalAmountTest struct {
+	s        string
+	currency Currency
+	want     int
+	wantErr  bool
+}
+
+func TestAmountFromDecimalIn(t *testing.T) {
+	tests := []decimalAmountTest{
+		{"12.34", CurrencyPLN, 1234, false},
+		{"12,34", CurrencyPLN, 1234, false},
+		{"12,3", CurrencyPLN, 1230, false},
+		{" 0.01 ", CurrencyPLN, 1, false},
+		{"12.345", CurrencyPLN, 0, true},
+		{"12.", CurrencyPLN, 0, true},
+		{".5", CurrencyPLN, 0, true},
+		{"", CurrencyPLN, 0, true},
+		{"-1.00", CurrencyPLN, 0, true},
+		{"1 000.00", CurrencyPLN, 0, true},
+		{"12.34", "USD", 0, true},
+		{"12.34", "", 0, true},
+	}
+	for _, currency := range []Currency{CurrencyEUR, CurrencyGBP, CurrencyCZK} {
+		tests = append(tests,
+			decimalAmountTest{"99", currency, 9900, false},
+			decimalAmountTest{"99.99", currency, 9999, false},
+			decimalAmountTest{"99,99", currency, 9999, false},
+			decimalAmountTest{"0,5", currency, 50, false},
+			decimalAmountTest{"99.999", currency, 0, true},
+			decimalAmountTest{"99,999", currency, 0, true},
+			decimalAmountTest{"99.", currency, 0, true},
+			decimalAmountTest{"99,", currency, 0, true},
+		)
+	}
+
+	for _, tt := range tests {
+		got, err := AmountFromDecimalIn(tt.s, tt.currency)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("AmountFromDecimalIn(%q, %q) = %d, %v, want %d, error %t", tt.s, tt.currency, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCurrencyDecimals(t *testing.T) {
+	tests := map[Currency]int{
+		CurrencyPLN: 2,
+		CurrencyEUR: 2,
+		CurrencyGBP: 2,
+		CurrencyCZK: 2,
+		"USD":       0,
+	}
+
+	for currency, want := range tests {
+		if got := currency.Decimals(); got != want {
+			t.Errorf("%q.Decimals() = %d, want %d", currency, got, want)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   int
+		currency Currency
+		want     string
+	}{
+		{123456, CurrencyPLN, "1 234,56 zł"},
+		{123456, CurrencyGBP, "£1,234.56"},
+		{5, CurrencyEUR, "0,05 €"},
+		{-123456, CurrencyGBP, "-£1,234.56"},
+		{123456, "XYZ", "1234.56 XYZ"},
+		{math.MaxInt, CurrencyPLN, "92 233 720 368 547 758,07 zł"},
+		{math.MinInt, CurrencyPLN, "-92 233 720 368 547 758,08 zł"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatAmount(%d, %q) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
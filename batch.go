@@ -0,0 +1,67 @@
+package przelewy24
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// BatchVerificationResult is the outcome of verifying one of the notifications passed to VerifyTransactions.
+type BatchVerificationResult struct {
+	Notification NotificationParams
+	Result       *VerificationResult
+	Err          error
+}
+
+// VerifyTransactions verifies many transactions concurrently, sending at most
+// Config.Concurrency requests at once. The results are in the order of data. A
+// failure of one transaction does not stop the others; the returned error joins
+// the errors of all the failed ones.
+func (p24 *p24) VerifyTransactions(data []NotificationParams) ([]BatchVerificationResult, error) {
+	return p24.VerifyTransactionsContext(context.Background(), data)
+}
+
+// VerifyTransactionsContext is like VerifyTransactions but the requests are bound to ctx.
+func (p24 *p24) VerifyTransactionsContext(ctx context.Context, data []NotificationParams) ([]BatchVerificationResult, error) {
+	results := make([]BatchVerificationResult, len(data))
+	p24.forEachConcurrently(len(data), func(i int) {
+		result, err := p24.VerifyTransactionContext(ctx, data[i])
+		results[i] = BatchVerificationResult{
+			Notification: data[i],
+			Result:       result,
+			Err:          err,
+		}
+	})
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// forEachConcurrently calls fn for every index below n, running at most p24.concurrency calls at once.
+func (p24 *p24) forEachConcurrently(n int, fn func(i int)) {
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(p24.concurrency, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+}
@@ -0,0 +1,173 @@
+package przelewy24
+
+import (
+	"context"
+	"net/url"
+)
+
+type BlikChargeResponse struct {
+	Data struct {
+		OrderId int64  `json:"orderId"`
+		Message string `json:"message"`
+	} `json:"data"`
+	BaseResponse
+}
+
+// BlikResult tells whether a BLIK charge was accepted. Accepted only means that
+// the payment was sent to the customer's banking app for confirmation, the final
+// status still comes in a notification.
+type BlikResult struct {
+	Accepted bool
+	OrderId  int64
+	Message  string
+	// Alias is the alias charged or registered with the payment, to be stored for next payments.
+	Alias *BlikAlias
+}
+
+const BlikAliasTypeAlias = "alias"
+
+// BlikAlias identifies the BLIK account of a returning customer.
+type BlikAlias struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+}
+
+type BlikAliasesResponse struct {
+	Data []BlikAlias `json:"data"`
+	BaseResponse
+}
+
+// ChargeBlik charges a registered transaction identified by token with a 6 digit BLIK code.
+func (p24 *p24) ChargeBlik(token string, blikCode string) (*BlikResult, error) {
+	return p24.ChargeBlikContext(context.Background(), token, blikCode)
+}
+
+// ChargeBlikContext is like ChargeBlik but the request is bound to ctx.
+func (p24 *p24) ChargeBlikContext(ctx context.Context, token string, blikCode string) (*BlikResult, error) {
+	return p24.ChargeBlikWithAliasContext(ctx, token, blikCode, nil)
+}
+
+// ChargeBlikWithAlias is like ChargeBlik, but also registers alias for the customer's
+// BLIK account, so that their next payments can be charged with ChargeBlikByAlias
+// without a code. The alias is returned in the result once the charge is accepted.
+func (p24 *p24) ChargeBlikWithAlias(token string, blikCode string, alias *BlikAlias) (*BlikResult, error) {
+	return p24.ChargeBlikWithAliasContext(context.Background(), token, blikCode, alias)
+}
+
+// ChargeBlikWithAliasContext is like ChargeBlikWithAlias but the request is bound to ctx.
+func (p24 *p24) ChargeBlikWithAliasContext(ctx context.Context, token string, blikCode string, alias *BlikAlias) (*BlikResult, error) {
+	if !isBlikCode(blikCode) {
+		return nil, ErrInvalidBlikCode
+	}
+
+	payload := struct {
+		Token      string `json:"token"`
+		BlikCode   string `json:"blikCode"`
+		AliasValue string `json:"aliasValue,omitempty"`
+		AliasLabel string `json:"aliasLabel,omitempty"`
+	}{
+		Token:    token,
+		BlikCode: blikCode,
+	}
+	if alias != nil {
+		payload.AliasValue = alias.Value
+		payload.AliasLabel = alias.Label
+	}
+
+	return p24.chargeBlik(ctx, "/api/v1/paymentMethod/blik/chargeByCode", payload, alias)
+}
+
+// ChargeBlikByAlias charges a registered transaction identified by token using an alias
+// registered with ChargeBlikWithAlias. The customer only confirms it in the banking app.
+func (p24 *p24) ChargeBlikByAlias(token string, alias BlikAlias) (*BlikResult, error) {
+	return p24.ChargeBlikByAliasContext(context.Background(), token, alias)
+}
+
+// ChargeBlikByAliasContext is like ChargeBlikByAlias but the request is bound to ctx.
+func (p24 *p24) ChargeBlikByAliasContext(ctx context.Context, token string, alias BlikAlias) (*BlikResult, error) {
+	aliasType := alias.Type
+	if aliasType == "" {
+		aliasType = BlikAliasTypeAlias
+	}
+
+	payload := struct {
+		Token      string `json:"token"`
+		Type       string `json:"type"`
+		AliasValue string `json:"aliasValue"`
+		AliasLabel string `json:"aliasLabel,omitempty"`
+	}{
+		Token:      token,
+		Type:       aliasType,
+		AliasValue: alias.Value,
+		AliasLabel: alias.Label,
+	}
+
+	return p24.chargeBlik(ctx, "/api/v1/paymentMethod/blik/chargeByAlias", payload, &alias)
+}
+
+// BlikAliases returns the BLIK aliases registered for the customer with email.
+func (p24 *p24) BlikAliases(email string) ([]BlikAlias, error) {
+	return p24.BlikAliasesContext(context.Background(), email)
+}
+
+// BlikAliasesContext is like BlikAliases but the request is bound to ctx.
+func (p24 *p24) BlikAliasesContext(ctx context.Context, email string) ([]BlikAlias, error) {
+	endpoint := p24.baseURL + "/api/v1/paymentMethod/blik/getAliasesByEmail/" + url.PathEscape(email)
+
+	var respBody BlikAliasesResponse
+	resp, err := p24.sendIdempotentRequest(ctx, "GET", endpoint, nil, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, resp.apiError(respBody.BaseResponse)
+	}
+
+	return respBody.Data, nil
+}
+
+func (p24 *p24) chargeBlik(ctx context.Context, path string, payload any, alias *BlikAlias) (*BlikResult, error) {
+	var respBody BlikChargeResponse
+	resp, err := p24.sendRequest(ctx, "POST", p24.baseURL+path, payload, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == 200 || resp.StatusCode == 201:
+		result := &BlikResult{
+			Accepted: true,
+			OrderId:  respBody.Data.OrderId,
+			Message:  respBody.Data.Message,
+		}
+		if alias != nil && alias.Value != "" {
+			result.Alias = alias
+		}
+
+		return result, nil
+	case resp.StatusCode == 400:
+		// The code or alias was rejected, e.g. it expired or was already used.
+		return &BlikResult{
+			Accepted: false,
+			Message:  respBody.Error.Message,
+		}, nil
+	default:
+		return nil, resp.apiError(respBody.BaseResponse)
+	}
+}
+
+func isBlikCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,159 @@
+package przelewy24
+
+import "context"
+
+// ChargeParams describes a recurring card charge. It is registered as a regular
+// transaction, so it is signed the same way, with MethodRefId set to the card reference.
+type ChargeParams = TransactionParams
+
+type ChargeResult struct {
+	Token     string
+	OrderId   int64
+	SessionId string
+}
+
+type CardChargeResponse struct {
+	Data struct {
+		OrderId   int64  `json:"orderId"`
+		SessionId string `json:"sessionId"`
+	} `json:"data"`
+	BaseResponse
+}
+
+// ChargeByToken charges a card saved during an earlier payment, identified by
+// refId, without any interaction from the customer. The sessionId of params is
+// what makes the charge idempotent: a transaction cannot be registered twice with
+// the same sessionId, so persist it before charging to be able to resubmit safely.
+func (p24 *p24) ChargeByToken(refId string, params ChargeParams) (*ChargeResult, error) {
+	return p24.ChargeByTokenContext(context.Background(), refId, params)
+}
+
+// ChargeByTokenContext is like ChargeByToken but the requests are bound to ctx.
+func (p24 *p24) ChargeByTokenContext(ctx context.Context, refId string, params ChargeParams) (*ChargeResult, error) {
+	params.MethodRefId = refId
+
+	registered, err := p24.RegisterTransactionResultContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := struct {
+		Token string `json:"token"`
+	}{
+		Token: registered.Token,
+	}
+
+	url := p24.baseURL + "/api/v1/card/charge"
+
+	var respBody CardChargeResponse
+	resp, err := p24.sendRequest(ctx, "POST", url, payload, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		return nil, resp.apiError(respBody.BaseResponse)
+	}
+
+	return &ChargeResult{
+		Token:     registered.Token,
+		OrderId:   respBody.Data.OrderId,
+		SessionId: respBody.Data.SessionId,
+	}, nil
+}
+
+// CardData is the card of the customer, for merchants allowed to handle it (PCI DSS).
+type CardData struct {
+	CardNumber string `json:"cardNumber"`
+	// CardDate is the expiry date of the card, formatted as MMYYYY.
+	CardDate   string `json:"cardDate"`
+	Cvv        string `json:"cvv"`
+	ClientName string `json:"clientName"`
+}
+
+// CardChargeOutcome tells what has to happen after a card charge was accepted.
+type CardChargeOutcome int
+
+const (
+	// CardChargeCompleted means the charge needs nothing more from the customer.
+	// The final status comes in a notification.
+	CardChargeCompleted CardChargeOutcome = iota
+	// CardChargeRedirect means the customer has to authorize the payment with 3-D Secure
+	// on the page of their bank, at CardChargeResult.RedirectUrl.
+	CardChargeRedirect
+)
+
+type CardChargeResult struct {
+	Outcome     CardChargeOutcome
+	OrderId     int64
+	SessionId   string
+	RedirectUrl string
+}
+
+type CardPayResponse struct {
+	Data struct {
+		OrderId     int64  `json:"orderId"`
+		SessionId   string `json:"sessionId"`
+		RedirectUrl string `json:"redirectUrl"`
+	} `json:"data"`
+	BaseResponse
+}
+
+// ChargeCard pays for a registered transaction identified by token with card.
+func (p24 *p24) ChargeCard(token string, card CardData) (*CardChargeResult, error) {
+	return p24.ChargeCardContext(context.Background(), token, card)
+}
+
+// ChargeCardContext is like ChargeCard but the request is bound to ctx.
+func (p24 *p24) ChargeCardContext(ctx context.Context, token string, card CardData) (*CardChargeResult, error) {
+	payload := struct {
+		TransactionToken string `json:"transactionToken"`
+		CardData
+	}{
+		TransactionToken: token,
+		CardData:         card,
+	}
+
+	return p24.chargeCard(ctx, "/api/v1/card/pay", payload)
+}
+
+// ChargeCardWith3DS charges a registered transaction identified by token, bound to a saved
+// card with TransactionParams.MethodRefId, letting the bank require 3-D Secure authorization.
+func (p24 *p24) ChargeCardWith3DS(token string) (*CardChargeResult, error) {
+	return p24.ChargeCardWith3DSContext(context.Background(), token)
+}
+
+// ChargeCardWith3DSContext is like ChargeCardWith3DS but the request is bound to ctx.
+func (p24 *p24) ChargeCardWith3DSContext(ctx context.Context, token string) (*CardChargeResult, error) {
+	payload := struct {
+		Token string `json:"token"`
+	}{
+		Token: token,
+	}
+
+	return p24.chargeCard(ctx, "/api/v1/card/chargeWith3ds", payload)
+}
+
+func (p24 *p24) chargeCard(ctx context.Context, path string, payload any) (*CardChargeResult, error) {
+	var respBody CardPayResponse
+	resp, err := p24.sendRequest(ctx, "POST", p24.baseURL+path, payload, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		return nil, resp.apiError(respBody.BaseResponse)
+	}
+
+	result := &CardChargeResult{
+		Outcome:     CardChargeCompleted,
+		OrderId:     respBody.Data.OrderId,
+		SessionId:   respBody.Data.SessionId,
+		RedirectUrl: respBody.Data.RedirectUrl,
+	}
+	if result.RedirectUrl != "" {
+		result.Outcome = CardChargeRedirect
+	}
+
+	return result, nil
+}
@@ -0,0 +1,27 @@
+package przelewy24
+
+// Channel is a bitmask of the groups of payment methods offered to the customer.
+type Channel int
+
+const (
+	ChannelCard                Channel = 1 // cards, Apple Pay and Google Pay
+	ChannelTransfer            Channel = 2
+	ChannelTraditionalTransfer Channel = 4
+	ChannelAll247              Channel = 16 // all methods available 24/7
+	ChannelPrepayment          Channel = 32
+	ChannelPayByLink           Channel = 64
+	ChannelInstallments        Channel = 128
+	ChannelWallets             Channel = 256
+	ChannelCardOnly            Channel = 4096
+	ChannelBlik                Channel = 8192
+	ChannelAllExceptBlik       Channel = 16384
+)
+
+const allChannels = ChannelCard | ChannelTransfer | ChannelTraditionalTransfer | ChannelAll247 |
+	ChannelPrepayment | ChannelPayByLink | ChannelInstallments | ChannelWallets | ChannelCardOnly |
+	ChannelBlik | ChannelAllExceptBlik
+
+// Valid reports whether c is made only of the documented channels.
+func (c Channel) Valid() bool {
+	return c > 0 && c&^allChannels == 0
+}
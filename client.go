@@ -0,0 +1,88 @@
+package przelewy24
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// Client is the API of Przelewy24 as exposed by the client returned by New.
+// Depend on it instead of the concrete client to replace the gateway in tests.
+type Client interface {
+	TestAccess() (bool, error)
+	TestAccessContext(ctx context.Context) (bool, error)
+	CheckEnvironment() error
+	CheckEnvironmentContext(ctx context.Context) error
+	ForPos(posId int) Client
+	ForSalesChannel(name string) (Client, error)
+
+	RegisterTransaction(data TransactionParams, opts ...TransactionOption) (string, error)
+	RegisterTransactionContext(ctx context.Context, data TransactionParams, opts ...TransactionOption) (string, error)
+	RegisterTransactionResult(data TransactionParams, opts ...TransactionOption) (*RegisterResult, error)
+	RegisterTransactionResultContext(ctx context.Context, data TransactionParams, opts ...TransactionOption) (*RegisterResult, error)
+	RegisterTransactionQR(data TransactionParams, qr QROptions, opts ...TransactionOption) (*QRRegisterResult, error)
+	RegisterTransactionQRContext(ctx context.Context, data TransactionParams, qr QROptions, opts ...TransactionOption) (*QRRegisterResult, error)
+	RegisterTransactionDryRun(data TransactionParams, opts ...TransactionOption) ([]byte, error)
+	RegisterTransactionForSDK(data TransactionParams, opts ...TransactionOption) (*SDKHandoff, error)
+	RegisterTransactionForSDKContext(ctx context.Context, data TransactionParams, opts ...TransactionOption) (*SDKHandoff, error)
+	PrepareTransaction(data TransactionParams, opts ...TransactionOption) (*PreparedTransaction, error)
+	RedirectURL(token string) string
+
+	VerifyTransaction(data NotificationParams) (*VerificationResult, error)
+	VerifyTransactionContext(ctx context.Context, data NotificationParams) (*VerificationResult, error)
+	VerifyTransactions(data []NotificationParams) ([]BatchVerificationResult, error)
+	VerifyTransactionsContext(ctx context.Context, data []NotificationParams) ([]BatchVerificationResult, error)
+	VerifyNotificationSignature(data NotificationParams) bool
+	VerifyFromRequest(r *http.Request) (*NotificationParams, error)
+	NotificationHandler(onPaid func(NotificationParams) error) http.Handler
+	TransactionNotificationHandler(onPaid func(NotificationParams, *TransactionInfo) error) http.Handler
+	SignedReturnURL(returnURL string, sessionId string, params url.Values) (string, error)
+	VerifyReturnURL(r *http.Request) (*ReturnParams, error)
+
+	GetTransactionBySessionId(sessionId string) (*TransactionInfo, error)
+	GetTransactionBySessionIdContext(ctx context.Context, sessionId string) (*TransactionInfo, error)
+	GetTransactionByOrderId(orderId int64) (*TransactionInfo, error)
+	GetTransactionByOrderIdContext(ctx context.Context, orderId int64) (*TransactionInfo, error)
+	SearchTransactions(filter TransactionFilter) (*TransactionPage, error)
+	SearchTransactionsContext(ctx context.Context, filter TransactionFilter) (*TransactionPage, error)
+	SearchTransactionPages(ctx context.Context, filter TransactionFilter) iter.Seq2[*TransactionPage, error]
+	SearchAllTransactions(filter TransactionFilter) ([]TransactionInfo, error)
+	SearchAllTransactionsContext(ctx context.Context, filter TransactionFilter) ([]TransactionInfo, error)
+	Reconcile(orders []LocalOrder) ([]Discrepancy, error)
+	ReconcileContext(ctx context.Context, orders []LocalOrder) ([]Discrepancy, error)
+	TransferInstructions(sessionId string) (*TransferInstructions, error)
+	TransferInstructionsContext(ctx context.Context, sessionId string) (*TransferInstructions, error)
+
+	PaymentMethods(lang string) ([]PaymentMethod, error)
+	PaymentMethodsContext(ctx context.Context, lang string) ([]PaymentMethod, error)
+	PaymentMethodsFiltered(lang string, filter PaymentMethodsFilter) ([]PaymentMethod, error)
+	PaymentMethodsFilteredContext(ctx context.Context, lang string, filter PaymentMethodsFilter) ([]PaymentMethod, error)
+
+	Refund(data RefundRequest) ([]RefundResult, error)
+	RefundContext(ctx context.Context, data RefundRequest) ([]RefundResult, error)
+	RefundBySessionId(sessionId string, amount int, description string) ([]RefundResult, error)
+	RefundBySessionIdContext(ctx context.Context, sessionId string, amount int, description string) ([]RefundResult, error)
+	ListRefunds(orderId int64) ([]RefundInfo, error)
+	ListRefundsContext(ctx context.Context, orderId int64) ([]RefundInfo, error)
+	GetRefundStatus(orderId int64, requestId string) (RefundStatus, error)
+	GetRefundStatusContext(ctx context.Context, orderId int64, requestId string) (RefundStatus, error)
+
+	ChargeBlik(token string, blikCode string) (*BlikResult, error)
+	ChargeBlikContext(ctx context.Context, token string, blikCode string) (*BlikResult, error)
+	ChargeBlikWithAlias(token string, blikCode string, alias *BlikAlias) (*BlikResult, error)
+	ChargeBlikWithAliasContext(ctx context.Context, token string, blikCode string, alias *BlikAlias) (*BlikResult, error)
+	ChargeBlikByAlias(token string, alias BlikAlias) (*BlikResult, error)
+	ChargeBlikByAliasContext(ctx context.Context, token string, alias BlikAlias) (*BlikResult, error)
+	BlikAliases(email string) ([]BlikAlias, error)
+	BlikAliasesContext(ctx context.Context, email string) ([]BlikAlias, error)
+
+	ChargeByToken(refId string, params ChargeParams) (*ChargeResult, error)
+	ChargeByTokenContext(ctx context.Context, refId string, params ChargeParams) (*ChargeResult, error)
+	ChargeCard(token string, card CardData) (*CardChargeResult, error)
+	ChargeCardContext(ctx context.Context, token string, card CardData) (*CardChargeResult, error)
+	ChargeCardWith3DS(token string) (*CardChargeResult, error)
+	ChargeCardWith3DSContext(ctx context.Context, token string) (*CardChargeResult, error)
+}
+
+var _ Client = (*p24)(nil)
@@ -0,0 +1,71 @@
+package przelewy24
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// checkout is code of an application depending on the Client interface rather than on
+// the concrete client.
+func checkout(client Client, data TransactionParams) (string, error) {
+	if _, err := client.TestAccess(); err != nil {
+		return "", err
+	}
+
+	result, err := client.RegisterTransactionResult(data)
+	if err != nil {
+		return "", err
+	}
+
+	return client.RedirectURL(result.Token), nil
+}
+
+func TestClientInterface(t *testing.T) {
+	var posIds []string
+	var client Client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		posId, _, _ := r.BasicAuth()
+		posIds = append(posIds, posId)
+
+		switch r.URL.Path {
+		case "/api/v1/testAccess":
+			w.Write([]byte(`{"data":true,"error":""}`))
+		case "/api/v1/transaction/register":
+			registerHandler(w, r)
+		case "/api/v1/transaction/verify":
+			w.Write([]byte(`{"data":{"status":"success"},"responseCode":0}`))
+		case "/api/v1/transaction/by/sessionId/order-1":
+			w.Write([]byte(`{"data":{"orderId":1,"sessionId":"order-1","status":2,"amount":1000,"currency":"PLN"},"responseCode":0}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}, Config{})
+
+	url, err := checkout(client, testTransaction("order-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(url, "/trnRequest/TOKEN") {
+		t.Errorf("got redirect url %s", url)
+	}
+
+	result, err := client.VerifyTransaction(NotificationParams{SessionId: "order-1", OrderId: 1, Amount: 1000, Currency: CurrencyPLN})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Verified() {
+		t.Errorf("got status %q, want verified", result.Status)
+	}
+
+	info, err := client.ForPos(2000).GetTransactionBySessionId("order-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Status != TransactionStatusPaid || info.Amount != 1000 {
+		t.Errorf("got transaction %+v", info)
+	}
+
+	if want := []string{"1000", "1000", "1000", "2000"}; strings.Join(posIds, ",") != strings.Join(want, ",") {
+		t.Errorf("authenticated as %v, want %v", posIds, want)
+	}
+}
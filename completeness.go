@@ -0,0 +1,67 @@
+package przelewy24
+
+// Completeness tells how much of the expected amount of a transaction was paid.
+type Completeness int
+
+const (
+	CompletenessUnpaid Completeness = iota
+	CompletenessPartial
+	CompletenessFull
+	CompletenessOverpaid
+)
+
+func (c Completeness) String() string {
+	switch c {
+	case CompletenessUnpaid:
+		return "unpaid"
+	case CompletenessPartial:
+		return "partially paid"
+	case CompletenessFull:
+		return "fully paid"
+	case CompletenessOverpaid:
+		return "overpaid"
+	default:
+		return "unknown"
+	}
+}
+
+// CompareAmounts compares an amount paid with the expected one, both in minor units.
+func CompareAmounts(paid int, expected int) Completeness {
+	switch {
+	case paid <= 0:
+		return CompletenessUnpaid
+	case paid < expected:
+		return CompletenessPartial
+	case paid == expected:
+		return CompletenessFull
+	default:
+		return CompletenessOverpaid
+	}
+}
+
+// Completeness compares the amount paid with the amount the transaction was registered
+// with. An OriginAmount of zero means the amount was paid in full.
+func (n NotificationParams) Completeness() Completeness {
+	if n.OriginAmount == 0 {
+		return CompareAmounts(n.Amount, n.Amount)
+	}
+
+	return CompareAmounts(n.Amount, n.OriginAmount)
+}
+
+// IsFullyPaid reports whether the transaction was verified and exactly expected, in
+// minor units, was paid. See PaidAgainst for telling the other cases apart.
+func (t *TransactionInfo) IsFullyPaid(expected int) bool {
+	return t.PaidAgainst(expected) == CompletenessFull
+}
+
+// PaidAgainst compares the amount of the transaction with expected, e.g. the total of
+// the order. Transactions that were not verified, see TransactionStatusPaid, or were
+// returned are unpaid regardless of their amount.
+func (t *TransactionInfo) PaidAgainst(expected int) Completeness {
+	if t.Status != TransactionStatusPaid {
+		return CompletenessUnpaid
+	}
+
+	return CompareAmounts(t.Amount, expected)
+}
@@ -0,0 +1,27 @@
+package przelewy24
+
+import "testing"
+
+func TestNotificationCompleteness(t *testing.T) {
+	tests := []struct {
+		amount       int
+		originAmount int
+		want         Completeness
+	}{
+		{1000, 1000, CompletenessFull},
+		{1000, 0, CompletenessFull},
+		{600, 1000, CompletenessPartial},
+		{1200, 1000, CompletenessOverpaid},
+		{0, 1000, CompletenessUnpaid},
+	}
+
+	for _, tt := range tests {
+		n := NotificationParams{Amount: tt.amount, OriginAmount: tt.originAmount}
+		if got := n.Completeness(); got != tt.want {
+			t.Errorf("%d of %d: got %v, want %v", tt.amount, tt.originAmount, got, tt.want)
+		}
+		if got, want := n.IsPartial(), tt.want == CompletenessPartial; got != want {
+			t.Errorf("%d of %d: IsPartial() = %t, want %t", tt.amount, tt.originAmount, got, want)
+		}
+	}
+}
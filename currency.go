@@ -0,0 +1,31 @@
+package przelewy24
+
+// Currency is an ISO 4217 code of a currency accepted by the API.
+type Currency string
+
+const (
+	CurrencyPLN Currency = "PLN"
+	CurrencyEUR Currency = "EUR"
+	CurrencyGBP Currency = "GBP"
+	CurrencyCZK Currency = "CZK"
+)
+
+// Valid reports whether c is one of the currencies supported by Przelewy24.
+func (c Currency) Valid() bool {
+	switch c {
+	case CurrencyPLN, CurrencyEUR, CurrencyGBP, CurrencyCZK:
+		return true
+	default:
+		return false
+	}
+}
+
+// Decimals returns how many digits of an amount in c are minor units, e.g. 2 for
+// grosze. All the supported currencies have 2; it is 0 for unsupported ones.
+func (c Currency) Decimals() int {
+	if !c.Valid() {
+		return 0
+	}
+
+	return 2
+}
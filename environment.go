@@ -0,0 +1,56 @@
+package przelewy24
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Environment is an installation of Przelewy24 the client talks to.
+type Environment string
+
+const (
+	EnvironmentProduction Environment = "production"
+	EnvironmentSandbox    Environment = "sandbox"
+)
+
+var environmentURLs = map[Environment]string{
+	EnvironmentProduction: "https://secure.przelewy24.pl",
+	EnvironmentSandbox:    "https://sandbox.przelewy24.pl",
+}
+
+// BaseURL returns the address of the environment, or an empty string for an unknown one.
+func (e Environment) BaseURL() string {
+	return environmentURLs[e]
+}
+
+// CheckEnvironment checks the credentials with TestAccess, e.g. at startup. When they are
+// rejected by the configured environment but accepted by the other one, the returned error
+// matches ErrEnvironmentMismatch and names the environment they belong to. Otherwise the
+// error of TestAccess is returned as it is. Only the sandbox and production environments
+// are compared, a client with a custom BaseURL is just checked with TestAccess.
+func (p24 *p24) CheckEnvironment() error {
+	return p24.CheckEnvironmentContext(context.Background())
+}
+
+// CheckEnvironmentContext is like CheckEnvironment but the requests are bound to ctx.
+func (p24 *p24) CheckEnvironmentContext(ctx context.Context) error {
+	_, err := p24.TestAccessContext(ctx)
+	if err == nil || !errors.Is(err, ErrUnauthorized) || p24.baseURL != p24.environment.BaseURL() {
+		return err
+	}
+
+	other := EnvironmentSandbox
+	if p24.environment == EnvironmentSandbox {
+		other = EnvironmentProduction
+	}
+
+	client := *p24
+	client.environment = other
+	client.baseURL = other.BaseURL()
+	if _, otherErr := client.TestAccessContext(ctx); otherErr != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: the credentials are valid in the %s environment, but the client is configured for %s", ErrEnvironmentMismatch, other, p24.environment)
+}
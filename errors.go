@@ -0,0 +1,188 @@
+package przelewy24
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnauthorized matches APIErrors caused by the API rejecting the posId and apiKey.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrVerificationMismatch matches APIErrors returned when the API does not confirm
+	// a transaction, e.g. because its amount or sign does not match.
+	ErrVerificationMismatch = errors.New("verification mismatch")
+	// ErrTransactionNotFound matches APIErrors returned by transaction lookups when there is no such transaction.
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrRefundNotFound is returned by GetRefundStatus when the transaction has no refund with the given requestId.
+	ErrRefundNotFound = errors.New("refund not found")
+	// ErrAlreadyRefunded is returned by RefundBySessionId when the refunds of the transaction
+	// would exceed its amount.
+	ErrAlreadyRefunded = errors.New("transaction already refunded")
+	// ErrNotManualTransfer is returned by TransferInstructions for transactions not paid by a manual bank transfer.
+	ErrNotManualTransfer = errors.New("transaction is not paid by manual transfer")
+
+	// ErrInvalidSignature is returned when the sign of a notification or a return url does not match the crc.
+	ErrInvalidSignature = errors.New("invalid sign")
+
+	// ErrMissingCredentials is returned by calls made with a client configured without an apiKey or crc.
+	ErrMissingCredentials = errors.New("missing apiKey or crc")
+
+	ErrInvalidBlikCode = errors.New("blik code must consist of exactly 6 digits")
+	// ErrInvalidAmount matches ValidationErrors of amounts that are not positive or exceed MaxAmount,
+	// or the amount of the transaction to refund.
+	ErrInvalidAmount = errors.New("invalid amount")
+	// ErrInvalidEmail matches ValidationErrors of a missing or malformed Email; the message quotes it.
+	ErrInvalidEmail = errors.New("invalid email")
+	// ErrEnvironmentMismatch is returned by CheckEnvironment when the credentials belong to
+	// the other environment, e.g. sandbox ones used against production.
+	ErrEnvironmentMismatch = errors.New("credentials belong to another environment")
+)
+
+// APIError is returned when the API responds with a non-successful status.
+type APIError struct {
+	StatusCode   int
+	ResponseCode int
+	Code         int
+	Message      string
+	// Fields holds per-field validation messages, keyed by the name of the rejected field.
+	Fields map[string]string
+	// Err is a sentinel error describing the failure, if any.
+	Err error
+
+	// RequestId is the X-Request-Id header of the response and Body its raw body,
+	// to be attached to logs and support tickets.
+	RequestId string
+	Body      []byte
+	// Header holds the headers of the response, e.g. the rate limiting ones, see RetryAfter.
+	Header http.Header
+	// Hint suggests a likely cause of the failure not reported by the API, if there is one,
+	// e.g. credentials of the other environment for a 401.
+	Hint string
+}
+
+// RetryAfter returns the delay the API asked for in the Retry-After header of the
+// response, typically sent with a 429 or 503 status, and whether there was one.
+func (e *APIError) RetryAfter() (time.Duration, bool) {
+	value := e.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if len(e.Fields) > 0 {
+		names := make([]string, 0, len(e.Fields))
+		for name := range e.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		details := make([]string, 0, len(names))
+		for _, name := range names {
+			details = append(details, fmt.Sprintf("%s: %s", name, e.Fields[name]))
+		}
+
+		if message != "" {
+			message += "; "
+		}
+		message += strings.Join(details, "; ")
+	}
+
+	code := e.Code
+	if code == 0 {
+		code = e.StatusCode
+	}
+
+	if e.Hint != "" {
+		return fmt.Sprintf("Response code: %d\nError: %s\nHint: %s", code, message, e.Hint)
+	}
+
+	return fmt.Sprintf("Response code: %d\nError: %s", code, message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrUnauthorized && e.StatusCode == http.StatusUnauthorized
+}
+
+// ResponseError is the error reported by the API, which is either a plain
+// message or an object mapping rejected fields to their messages.
+type ResponseError struct {
+	Message string
+	Fields  map[string]string
+}
+
+func (e *ResponseError) UnmarshalJSON(data []byte) error {
+	*e = ResponseError{}
+
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		e.Message = message
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Neither a string nor an object, e.g. null; keep the raw value for debugging.
+		if string(data) != "null" {
+			e.Message = string(data)
+		}
+		return nil
+	}
+
+	e.Fields = make(map[string]string, len(fields))
+	for name, raw := range fields {
+		var fieldMessage string
+		if err := json.Unmarshal(raw, &fieldMessage); err != nil {
+			fieldMessage = string(raw)
+		}
+		e.Fields[name] = fieldMessage
+	}
+
+	return nil
+}
+
+// maxErrorSnippet is how much of the body a ResponseFormatError includes in its message.
+const maxErrorSnippet = 200
+
+// ResponseFormatError is returned when a successful response of the API cannot be
+// decoded, e.g. because a proxy answered with an HTML page. Error responses that cannot
+// be decoded are returned as an APIError of their status instead.
+type ResponseFormatError struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	Err         error
+}
+
+func (e *ResponseFormatError) Error() string {
+	snippet := string(e.Body)
+	if len(snippet) > maxErrorSnippet {
+		snippet = strings.ToValidUTF8(snippet[:maxErrorSnippet], "") + "..."
+	}
+
+	return fmt.Sprintf("unexpected response with status %d and content type %q: %s", e.StatusCode, e.ContentType, snippet)
+}
+
+func (e *ResponseFormatError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,123 @@
+package przelewy24
+
+// Language of the payment page.
+type Language string
+
+const (
+	LanguageBulgarian  Language = "bg"
+	LanguageCzech      Language = "cs"
+	LanguageGerman     Language = "de"
+	LanguageEnglish    Language = "en"
+	LanguageSpanish    Language = "es"
+	LanguageFrench     Language = "fr"
+	LanguageCroatian   Language = "hr"
+	LanguageHungarian  Language = "hu"
+	LanguageItalian    Language = "it"
+	LanguageDutch      Language = "nl"
+	LanguagePolish     Language = "pl"
+	LanguagePortuguese Language = "pt"
+	LanguageSwedish    Language = "se"
+	LanguageSlovak     Language = "sk"
+	LanguageRomanian   Language = "ro"
+)
+
+// Valid reports whether l is one of the languages of the payment page.
+func (l Language) Valid() bool {
+	switch l {
+	case LanguageBulgarian, LanguageCzech, LanguageGerman, LanguageEnglish, LanguageSpanish,
+		LanguageFrench, LanguageCroatian, LanguageHungarian, LanguageItalian, LanguageDutch,
+		LanguagePolish, LanguagePortuguese, LanguageSwedish, LanguageSlovak, LanguageRomanian:
+		return true
+	default:
+		return false
+	}
+}
+
+// Country is an ISO 3166-1 alpha-2 code of the customer's country.
+type Country string
+
+const (
+	CountryAustria       Country = "AT"
+	CountryBelgium       Country = "BE"
+	CountryBulgaria      Country = "BG"
+	CountryCroatia       Country = "HR"
+	CountryCyprus        Country = "CY"
+	CountryCzechia       Country = "CZ"
+	CountryDenmark       Country = "DK"
+	CountryEstonia       Country = "EE"
+	CountryFinland       Country = "FI"
+	CountryFrance        Country = "FR"
+	CountryGermany       Country = "DE"
+	CountryGreece        Country = "GR"
+	CountryHungary       Country = "HU"
+	CountryIreland       Country = "IE"
+	CountryItaly         Country = "IT"
+	CountryLatvia        Country = "LV"
+	CountryLithuania     Country = "LT"
+	CountryLuxembourg    Country = "LU"
+	CountryMalta         Country = "MT"
+	CountryNetherlands   Country = "NL"
+	CountryNorway        Country = "NO"
+	CountryPoland        Country = "PL"
+	CountryPortugal      Country = "PT"
+	CountryRomania       Country = "RO"
+	CountrySlovakia      Country = "SK"
+	CountrySlovenia      Country = "SI"
+	CountrySpain         Country = "ES"
+	CountrySweden        Country = "SE"
+	CountrySwitzerland   Country = "CH"
+	CountryUkraine       Country = "UA"
+	CountryUnitedKingdom Country = "GB"
+	CountryUnitedStates  Country = "US"
+)
+
+var countryLanguages = map[Country]Language{
+	CountryAustria:       LanguageGerman,
+	CountryBelgium:       LanguageFrench,
+	CountryBulgaria:      LanguageBulgarian,
+	CountryCroatia:       LanguageCroatian,
+	CountryCyprus:        LanguageEnglish,
+	CountryCzechia:       LanguageCzech,
+	CountryDenmark:       LanguageEnglish,
+	CountryEstonia:       LanguageEnglish,
+	CountryFinland:       LanguageEnglish,
+	CountryFrance:        LanguageFrench,
+	CountryGermany:       LanguageGerman,
+	CountryGreece:        LanguageEnglish,
+	CountryHungary:       LanguageHungarian,
+	CountryIreland:       LanguageEnglish,
+	CountryItaly:         LanguageItalian,
+	CountryLatvia:        LanguageEnglish,
+	CountryLithuania:     LanguageEnglish,
+	CountryLuxembourg:    LanguageFrench,
+	CountryMalta:         LanguageEnglish,
+	CountryNetherlands:   LanguageDutch,
+	CountryNorway:        LanguageEnglish,
+	CountryPoland:        LanguagePolish,
+	CountryPortugal:      LanguagePortuguese,
+	CountryRomania:       LanguageRomanian,
+	CountrySlovakia:      LanguageSlovak,
+	CountrySlovenia:      LanguageEnglish,
+	CountrySpain:         LanguageSpanish,
+	CountrySweden:        LanguageSwedish,
+	CountrySwitzerland:   LanguageGerman,
+	CountryUkraine:       LanguageEnglish,
+	CountryUnitedKingdom: LanguageEnglish,
+	CountryUnitedStates:  LanguageEnglish,
+}
+
+// Valid reports whether c is one of the known countries.
+func (c Country) Valid() bool {
+	_, ok := countryLanguages[c]
+	return ok
+}
+
+// Language returns the language of the payment page best suited for customers
+// from c, falling back to English.
+func (c Country) Language() Language {
+	if language, ok := countryLanguages[c]; ok {
+		return language
+	}
+
+	return LanguageEnglish
+}
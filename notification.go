@@ -0,0 +1,115 @@
+package przelewy24
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// NotificationHandler returns a handler for notifications sent by Przelewy24 to urlStatus.
+// It checks the sign of every notification, verifies the transaction with the API and then
+// calls onPaid. A successful response is written only when all of these succeed, otherwise
+// Przelewy24 is told to send the notification again later. With Config.SeenStore set,
+// every notification is claimed in the store first, and ones already claimed, e.g. by a
+// concurrent delivery, are acknowledged without calling onPaid; a failed one is released
+// for its resend. Without a SeenStore, onPaid has to tolerate being called more than once
+// for a transaction.
+func (p24 *p24) NotificationHandler(onPaid func(NotificationParams) error) http.Handler {
+	return p24.notificationHandler(false, func(data NotificationParams, _ *TransactionInfo) error {
+		return onPaid(data)
+	})
+}
+
+// TransactionNotificationHandler is like NotificationHandler, but also fetches the
+// verified transaction with GetTransactionBySessionId and passes it to onPaid, for
+// the details missing from the notification, such as the email of the customer.
+func (p24 *p24) TransactionNotificationHandler(onPaid func(NotificationParams, *TransactionInfo) error) http.Handler {
+	return p24.notificationHandler(true, onPaid)
+}
+
+// notificationHandler implements NotificationHandler, fetching the transaction passed to
+// onPaid only when fetchTransaction is set.
+func (p24 *p24) notificationHandler(fetchTransaction bool, onPaid func(NotificationParams, *TransactionInfo) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// The cause is not written back, not to tell a forger what was wrong.
+		data, err := p24.VerifyFromRequest(r)
+		if err != nil {
+			http.Error(w, "invalid notification", http.StatusBadRequest)
+			return
+		}
+
+		fail := func(message string, status int) {
+			if p24.seenStore != nil {
+				// Przelewy24 resends the notification, which is then processed again, so
+				// it is released even when the request was cancelled.
+				_ = p24.seenStore.Release(context.WithoutCancel(r.Context()), data.SessionId, data.OrderId)
+			}
+			http.Error(w, message, status)
+		}
+
+		if p24.seenStore != nil {
+			claimed, err := p24.seenStore.Claim(r.Context(), data.SessionId, data.OrderId)
+			if err != nil {
+				http.Error(w, "processing failed", http.StatusInternalServerError)
+				return
+			}
+			if !claimed {
+				// Already processed, or being processed, only acknowledge it again.
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+
+		// A confirmation with a status other than success does not make the payment final.
+		if result, err := p24.VerifyTransactionContext(r.Context(), *data); err != nil || !result.Verified() {
+			fail("verification failed", http.StatusBadGateway)
+			return
+		}
+
+		var transaction *TransactionInfo
+		if fetchTransaction {
+			transaction, err = p24.GetTransactionBySessionIdContext(r.Context(), data.SessionId)
+			if err != nil {
+				fail("fetching transaction failed", http.StatusBadGateway)
+				return
+			}
+		}
+
+		if err := onPaid(*data, transaction); err != nil {
+			fail("processing failed", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// maxNotificationSize is the largest notification body accepted by VerifyFromRequest.
+const maxNotificationSize = 64 << 10
+
+// VerifyFromRequest decodes the notification sent by Przelewy24 in the body of r and
+// checks its sign with VerifyNotificationSignature. It does not contact the API, the
+// transaction still has to be confirmed with VerifyTransaction.
+func (p24 *p24) VerifyFromRequest(r *http.Request) (*NotificationParams, error) {
+	if err := p24.checkCredentials(); err != nil {
+		return nil, err
+	}
+
+	var data NotificationParams
+	err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxNotificationSize)).Decode(&data)
+	if err != nil {
+		return nil, fmt.Errorf("malformed notification: %w", err)
+	}
+
+	if !p24.VerifyNotificationSignature(data) {
+		return nil, ErrInvalidSignature
+	}
+
+	return &data, nil
+}
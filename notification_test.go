@@ -0,0 +1,149 @@
+package przelewy24
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testNotification returns a notification of sessionId signed with the test crc.
+func testNotification(sessionId string) NotificationParams {
+	data := NotificationParams{
+		MerchantId:   1000,
+		PosId:        1000,
+		SessionId:    sessionId,
+		Amount:       1000,
+		OriginAmount: 1000,
+		Currency:     CurrencyPLN,
+		OrderId:      312345678,
+		MethodId:     25,
+		Statement:    "p24-A1-B2-C3",
+	}
+	data.Sign = calculateNotificationSignature(SHA384Signer{}, data.MerchantId, data.PosId, data.SessionId, data.Amount, data.OriginAmount, data.Currency, data.OrderId, data.MethodId, data.Statement, "test-crc")
+
+	return data
+}
+
+// notify serves data as a notification by handler and returns the response status.
+func notify(t *testing.T, handler http.Handler, data NotificationParams) int {
+	t.Helper()
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/status", bytes.NewReader(body)))
+
+	return recorder.Code
+}
+
+// verifyHandler confirms every verification after delay.
+func verifyHandler(delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Write([]byte(`{"data":{"status":"success"},"responseCode":0}`))
+	}
+}
+
+func TestNotificationHandlerProcessesConcurrentDeliveriesOnce(t *testing.T) {
+	client := newTestClient(t, verifyHandler(50*time.Millisecond), Config{SeenStore: NewMemorySeenStore()})
+
+	var paid atomic.Int32
+	handler := client.NotificationHandler(func(NotificationParams) error {
+		paid.Add(1)
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if status := notify(t, handler, testNotification("order-1")); status != http.StatusOK {
+				t.Errorf("got status %d", status)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := paid.Load(); got != 1 {
+		t.Errorf("onPaid called %d times, want 1", got)
+	}
+}
+
+func TestNotificationHandlerReleasesFailedNotifications(t *testing.T) {
+	client := newTestClient(t, verifyHandler(0), Config{SeenStore: NewMemorySeenStore()})
+
+	var paid atomic.Int32
+	handler := client.NotificationHandler(func(NotificationParams) error {
+		if paid.Add(1) == 1 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	if status := notify(t, handler, testNotification("order-1")); status != http.StatusInternalServerError {
+		t.Errorf("got status %d for the failed delivery, want 500", status)
+	}
+	// The resend is processed, and a further one only acknowledged.
+	for range 2 {
+		if status := notify(t, handler, testNotification("order-1")); status != http.StatusOK {
+			t.Errorf("got status %d for a resend, want 200", status)
+		}
+	}
+
+	if got := paid.Load(); got != 2 {
+		t.Errorf("onPaid called %d times, want 2", got)
+	}
+}
+
+func TestNotificationHandlerHidesRejectionCause(t *testing.T) {
+	client := newTestClient(t, verifyHandler(0), Config{})
+	handler := client.NotificationHandler(func(NotificationParams) error {
+		t.Error("onPaid called")
+		return nil
+	})
+
+	tampered := testNotification("order-1")
+	tampered.Amount = 1
+	forged, err := json.Marshal(tampered)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, body := range map[string][]byte{
+		"sign":      forged,
+		"malformed": []byte(`{"sessionId":`),
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/status", bytes.NewReader(body)))
+
+		if recorder.Code != http.StatusBadRequest || recorder.Body.String() != "invalid notification\n" {
+			t.Errorf("%s: got %d %q, want 400 \"invalid notification\"", name, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestNotificationHandlerRequiresVerifiedStatus(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"status":"error"},"responseCode":0}`))
+	}, Config{SeenStore: NewMemorySeenStore()})
+	handler := client.NotificationHandler(func(NotificationParams) error {
+		t.Error("onPaid called for an unconfirmed payment")
+		return nil
+	})
+
+	// Both deliveries are rejected: the first one releases its claim.
+	for range 2 {
+		if status := notify(t, handler, testNotification("order-1")); status != http.StatusBadGateway {
+			t.Errorf("got status %d, want 502", status)
+		}
+	}
+}
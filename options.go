@@ -0,0 +1,64 @@
+package przelewy24
+
+import (
+	"net/http"
+	"time"
+)
+
+// TransactionOption modifies the parameters of a transaction being registered.
+type TransactionOption func(*TransactionParams)
+
+// WithMethod restricts the transaction to the payment method with methodId, see PaymentMethods.
+func WithMethod(methodId int) TransactionOption {
+	return func(data *TransactionParams) {
+		data.Method = methodId
+	}
+}
+
+// WithChannel restricts the transaction to the payment methods in channel.
+func WithChannel(channel Channel) TransactionOption {
+	return func(data *TransactionParams) {
+		data.Channel = channel
+	}
+}
+
+// WithWaitForResult makes the payment page wait for the result of the payment
+// before sending the customer back to urlReturn, see TransactionParams.WaitForResult.
+func WithWaitForResult() TransactionOption {
+	return func(data *TransactionParams) {
+		data.WaitForResult = true
+	}
+}
+
+// WithPos registers the transaction for merchantId and posId instead of the
+// configured ones, e.g. for another shop of the same account.
+func WithPos(merchantId int, posId int) TransactionOption {
+	return func(data *TransactionParams) {
+		data.MerchantId = merchantId
+		data.PosId = posId
+	}
+}
+
+// WithNormalizedEmail normalizes the Email of the transaction with NormalizeEmail
+// before it is validated, e.g. to accept " Jan@Example.com " typed into a form.
+func WithNormalizedEmail() TransactionOption {
+	return func(data *TransactionParams) {
+		data.Email = NormalizeEmail(data.Email)
+	}
+}
+
+// ClientOption configures a client created by New, beyond what Config covers.
+type ClientOption func(*p24)
+
+// WithRetryPolicy retries requests that are safe to repeat up to max times when retryOn
+// reports true for the outcome of an attempt, waiting baseDelay (200ms when not positive)
+// before the first retry and twice as long before every next one. A negative max disables
+// retries. It replaces Config.MaxRetries and Config.RetryDelay. The response passed to retryOn is nil when none was received, and its
+// body has been read already. When retryOn is nil, DefaultRetryOn is used. Charges are never retried.
+func WithRetryPolicy(max int, baseDelay time.Duration, retryOn func(*http.Response, error) bool) ClientOption {
+	return func(p24 *p24) {
+		p24.maxRetries = max
+		p24.retryBase = baseDelay
+		p24.retryOn = retryOn
+	}
+}
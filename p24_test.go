@@ -0,0 +1,323 @@
+package przelewy24
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// testTransaction returns params of a transaction that passes validation.
+func testTransaction(sessionId string) TransactionParams {
+	return TransactionParams{
+		SessionId:   sessionId,
+		Amount:      1000,
+		Currency:    CurrencyPLN,
+		Description: "Order " + sessionId,
+		Email:       "jan@example.com",
+		Country:     CountryPoland,
+		UrlReturn:   "https://shop.example.com/return",
+	}
+}
+
+// registerHandler answers registrations with a token.
+func registerHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`{"data":{"token":"TOKEN"},"responseCode":0}`))
+}
+
+func TestRegisterResultAmount(t *testing.T) {
+	client := newTestClient(t, registerHandler, Config{})
+
+	result, err := client.RegisterTransactionResult(testTransaction("order-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Notifications of partial payments of the transaction report it as OriginAmount.
+	if result.Amount != 1000 {
+		t.Errorf("Amount = %d, want 1000", result.Amount)
+	}
+	if result.Url != client.baseURL+"/trnRequest/TOKEN" {
+		t.Errorf("Url = %s", result.Url)
+	}
+}
+
+func TestRequestsAuthenticateAsPos(t *testing.T) {
+	var posId, apiKey string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		if posId, apiKey, ok = r.BasicAuth(); !ok {
+			t.Error("request without basic auth")
+		}
+		if r.URL.Path == "/api/v1/transaction/verify" {
+			w.Write([]byte(`{"data":{"status":"success"},"responseCode":0}`))
+			return
+		}
+		registerHandler(w, r)
+	}, Config{})
+
+	tests := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"register", func() error {
+			_, err := client.RegisterTransaction(testTransaction("order-1"))
+			return err
+		}, "1000"},
+		{"register with WithPos", func() error {
+			_, err := client.RegisterTransaction(testTransaction("order-2"), WithPos(1000, 2000))
+			return err
+		}, "2000"},
+		{"verify", func() error {
+			_, err := client.VerifyTransaction(NotificationParams{SessionId: "order-1", OrderId: 1, Amount: 1000, Currency: CurrencyPLN})
+			return err
+		}, "1000"},
+		{"verify for another POS", func() error {
+			_, err := client.VerifyTransaction(NotificationParams{PosId: 2000, SessionId: "order-2", OrderId: 2, Amount: 1000, Currency: CurrencyPLN})
+			return err
+		}, "2000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posId, apiKey = "", ""
+			if err := tt.call(); err != nil {
+				t.Fatal(err)
+			}
+			if posId != tt.want || apiKey != "test-api-key" {
+				t.Errorf("authenticated as %q:%q, want %q:test-api-key", posId, apiKey, tt.want)
+			}
+		})
+	}
+}
+
+// TestConcurrentRegistrations is meant to be run with -race.
+func TestConcurrentRegistrations(t *testing.T) {
+	var mu sync.Mutex
+	registered := map[string]bool{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var data signedTransaction
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		registered[data.SessionId] = true
+		mu.Unlock()
+		registerHandler(w, r)
+	}, Config{})
+
+	shared := testTransaction("")
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			data := shared
+			data.SessionId = fmt.Sprintf("order-%d", i)
+			var opts []TransactionOption
+			if i%2 == 0 {
+				opts = append(opts, WithPos(1000, 2000))
+			}
+			if _, err := client.RegisterTransaction(data, opts...); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(registered) != 50 {
+		t.Errorf("got %d registrations, want 50", len(registered))
+	}
+	if shared.MerchantId != 0 || shared.PosId != 0 || shared.Sign != "" {
+		t.Errorf("params of the caller changed to %+v", shared)
+	}
+}
+
+func TestRegisterMerchantAndPosOverride(t *testing.T) {
+	client := newTestClient(t, registerHandler, Config{MerchantId: 1000, PosId: 1001})
+
+	tests := []struct {
+		name         string
+		merchantId   int
+		posId        int
+		opts         []TransactionOption
+		wantMerchant int
+		wantPos      int
+	}{
+		{"defaults", 0, 0, nil, 1000, 1001},
+		{"posId", 0, 2001, nil, 1000, 2001},
+		{"merchantId and posId", 2000, 2001, nil, 2000, 2001},
+		{"WithPos", 0, 0, []TransactionOption{WithPos(3000, 3001)}, 3000, 3001},
+		{"WithPos over params", 2000, 2001, []TransactionOption{WithPos(3000, 3001)}, 3000, 3001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testTransaction("order-1")
+			data.MerchantId, data.PosId = tt.merchantId, tt.posId
+
+			body, err := client.RegisterTransactionDryRun(data, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var payload signedTransaction
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatal(err)
+			}
+
+			if payload.MerchantId != tt.wantMerchant || payload.PosId != tt.wantPos {
+				t.Errorf("registered for %d/%d, want %d/%d", payload.MerchantId, payload.PosId, tt.wantMerchant, tt.wantPos)
+			}
+			// The sign covers the merchantId actually sent.
+			if want := calculateRegistrationSignature(SHA384Signer{}, "order-1", tt.wantMerchant, 1000, CurrencyPLN, "test-crc"); payload.Sign != want {
+				t.Errorf("sign = %s, want %s", payload.Sign, want)
+			}
+		})
+	}
+}
+
+// readResponse returns the body in testdata/responses/name.
+func readResponse(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", "responses", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return body
+}
+
+// serveResponse answers every request with the body in testdata/responses/name and status.
+func serveResponse(t *testing.T, name string, status int) http.HandlerFunc {
+	body := readResponse(t, name)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(body)
+	}
+}
+
+// The bodies in testdata/responses are in the shape of the examples of the API
+// documentation, which spells the code of a successful response responseCode.
+func TestDecodeResponses(t *testing.T) {
+	t.Run("register", func(t *testing.T) {
+		var resp RegisterTransactionResponse
+		if err := json.Unmarshal(readResponse(t, "register.json"), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Data.Token != "3C07D0AF-8A2C-4753-A2FA-A3D6AF6ED5C1" || resp.ResponseCode != 0 {
+			t.Errorf("got %+v", resp)
+		}
+
+		client := newTestClient(t, serveResponse(t, "register.json", http.StatusOK), Config{})
+		result, err := client.RegisterTransactionResult(testTransaction("order-1"))
+		if err != nil || result.Token != resp.Data.Token {
+			t.Errorf("got %+v, %v", result, err)
+		}
+	})
+
+	t.Run("register invalid", func(t *testing.T) {
+		client := newTestClient(t, serveResponse(t, "register_invalid.json", http.StatusBadRequest), Config{})
+		_, err := client.RegisterTransaction(testTransaction("order-1"))
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("got %v, want an APIError", err)
+		}
+		if apiErr.Code != 400 || apiErr.Fields["sessionId"] != "Transaction with this sessionId already exists" || apiErr.Fields["urlReturn"] != "Invalid url" {
+			t.Errorf("got %+v", apiErr)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		client := newTestClient(t, serveResponse(t, "unauthorized.json", http.StatusUnauthorized), Config{})
+		_, err := client.TestAccess()
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, ErrUnauthorized) {
+			t.Fatalf("got %v, want an APIError matching ErrUnauthorized", err)
+		}
+		if apiErr.Code != 401 || apiErr.Message != "Incorrect authentication" {
+			t.Errorf("got %+v", apiErr)
+		}
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		var resp VerifyTransactionResponse
+		if err := json.Unmarshal(readResponse(t, "verify.json"), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Data.Status != "success" || resp.ResponseCode != 0 || resp.Code != 0 {
+			t.Errorf("got %+v", resp)
+		}
+
+		client := newTestClient(t, serveResponse(t, "verify.json", http.StatusOK), Config{})
+		result, err := client.VerifyTransaction(NotificationParams{SessionId: "order-1", OrderId: 1, Amount: 1000, Currency: CurrencyPLN})
+		if err != nil || !result.Verified() {
+			t.Errorf("got %+v, %v", result, err)
+		}
+	})
+
+	t.Run("test access", func(t *testing.T) {
+		client := newTestClient(t, serveResponse(t, "test_access.json", http.StatusOK), Config{})
+		if ok, err := client.TestAccess(); !ok || err != nil {
+			t.Errorf("got %t, %v", ok, err)
+		}
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		client := newTestClient(t, serveResponse(t, "transaction.json", http.StatusOK), Config{})
+		info, err := client.GetTransactionBySessionId("order-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := TransactionInfo{
+			Statement:         "p24-A1-B2-C3",
+			OrderId:           312345678,
+			SessionId:         "order-1",
+			Status:            TransactionStatusPaid,
+			Amount:            1000,
+			Currency:          CurrencyPLN,
+			Date:              "202401151230",
+			DateOfTransaction: "202401151228",
+			ClientEmail:       "jan@example.com",
+			PaymentMethod:     25,
+			Description:       "Order order-1",
+			ClientName:        "Jan Kowalski",
+			ClientAddress:     "ul. Kwiatowa 1",
+			ClientCity:        "Warszawa",
+			ClientPostcode:    "00-001",
+			Fee:               "0",
+		}
+		if *info != want {
+			t.Errorf("got %+v, want %+v", *info, want)
+		}
+	})
+
+	t.Run("transaction not found", func(t *testing.T) {
+		client := newTestClient(t, serveResponse(t, "transaction_not_found.json", http.StatusNotFound), Config{})
+		if _, err := client.GetTransactionBySessionId("order-1"); !errors.Is(err, ErrTransactionNotFound) {
+			t.Errorf("got %v, want %v", err, ErrTransactionNotFound)
+		}
+	})
+
+	t.Run("refund rejected", func(t *testing.T) {
+		client := newTestClient(t, serveResponse(t, "refund_rejected.json", http.StatusBadRequest), Config{})
+		results, err := client.Refund(NewRefundRequest(RefundItem{OrderId: 312345678, SessionId: "order-1", Amount: 400, Description: "Zwrot"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].Status || results[0].Message != "Incorrect amount" {
+			t.Errorf("got %+v", results)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package przelewy24
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+type PaymentMethod struct {
+	Name              string `json:"name"`
+	Id                int    `json:"id"`
+	Group             string `json:"group"`
+	Subgroup          string `json:"subgroup"`
+	Status            bool   `json:"status"`
+	ImgUrl            string `json:"imgUrl"`
+	MobileImgUrl      string `json:"mobileImgUrl"`
+	Mobile            bool   `json:"mobile"`
+	AvailabilityHours struct {
+		MondayToFriday string `json:"mondayToFriday"`
+		Saturday       string `json:"saturday"`
+		Sunday         string `json:"sunday"`
+	} `json:"availabilityHours"`
+}
+
+// PaymentMethodsFilter narrows down the methods returned by the API to those
+// available for the given amount and currency. Zero values are not sent.
+type PaymentMethodsFilter struct {
+	Amount   int
+	Currency Currency
+}
+
+type PaymentMethodsResponse struct {
+	Data []PaymentMethod `json:"data"`
+	BaseResponse
+}
+
+// PaymentMethods returns the payment methods available to the merchant, described in lang.
+// The API returns all of them at once, so unlike SearchTransactions there are no pages.
+func (p24 *p24) PaymentMethods(lang string) ([]PaymentMethod, error) {
+	return p24.PaymentMethodsContext(context.Background(), lang)
+}
+
+// PaymentMethodsContext is like PaymentMethods but the request is bound to ctx.
+func (p24 *p24) PaymentMethodsContext(ctx context.Context, lang string) ([]PaymentMethod, error) {
+	return p24.paymentMethods(ctx, lang, PaymentMethodsFilter{})
+}
+
+// PaymentMethodsFiltered returns the payment methods applicable to a transaction of
+// filter.Amount in filter.Currency: the API leaves out the methods that do not accept
+// them, and the methods it reports as currently unavailable (Status false) are left out
+// as well. When no method is applicable, e.g. for a currency the merchant has no methods
+// in, the returned slice is empty and the error nil.
+func (p24 *p24) PaymentMethodsFiltered(lang string, filter PaymentMethodsFilter) ([]PaymentMethod, error) {
+	return p24.PaymentMethodsFilteredContext(context.Background(), lang, filter)
+}
+
+// PaymentMethodsFilteredContext is like PaymentMethodsFiltered but the request is bound to ctx.
+func (p24 *p24) PaymentMethodsFilteredContext(ctx context.Context, lang string, filter PaymentMethodsFilter) ([]PaymentMethod, error) {
+	if filter.Amount < 0 {
+		return nil, &ValidationError{Field: "amount", Message: fmt.Sprintf("must not be negative, got %d", filter.Amount), Err: ErrInvalidAmount}
+	}
+	if filter.Currency != "" && !filter.Currency.Valid() {
+		return nil, &ValidationError{Field: "currency", Message: fmt.Sprintf("%q is not supported", filter.Currency)}
+	}
+
+	methods, err := p24.paymentMethods(ctx, lang, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	applicable := []PaymentMethod{}
+	for _, method := range methods {
+		if method.Status {
+			applicable = append(applicable, method)
+		}
+	}
+
+	return applicable, nil
+}
+
+// paymentMethods fetches the payment methods described in lang, passing filter to the API.
+func (p24 *p24) paymentMethods(ctx context.Context, lang string, filter PaymentMethodsFilter) ([]PaymentMethod, error) {
+	endpoint := p24.baseURL + "/api/v1/payment/methods/" + url.PathEscape(lang)
+
+	query := url.Values{}
+	if filter.Amount != 0 {
+		query.Set("amount", strconv.Itoa(filter.Amount))
+	}
+	if filter.Currency != "" {
+		query.Set("currency", string(filter.Currency))
+	}
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var respBody PaymentMethodsResponse
+	resp, err := p24.sendIdempotentRequest(ctx, "GET", endpoint, nil, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, resp.apiError(respBody.BaseResponse)
+	}
+
+	return respBody.Data, nil
+}
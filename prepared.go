@@ -0,0 +1,83 @@
+package przelewy24
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// PreparedTransaction is a template of transactions that differ only in their sessionId
+// and amount, e.g. top-ups of a fixed kind. The options, defaults and validation of the
+// other params are applied, and the params encoded into the body of the request, once
+// by PrepareTransaction; Send only validates and signs the sessionId and amount. Later
+// changes to the params passed to PrepareTransaction, e.g. to their Cart, do not affect
+// it. It is safe for concurrent use.
+type PreparedTransaction struct {
+	p24        *p24
+	merchantId int
+	posId      int
+	currency   Currency
+	// fields is the JSON object of the params other than sessionId and amount.
+	fields []byte
+}
+
+// PrepareTransaction applies opts and the defaults of the client to data and validates
+// it, except for its SessionId and Amount, which are given to Send.
+func (p24 *p24) PrepareTransaction(data TransactionParams, opts ...TransactionOption) (*PreparedTransaction, error) {
+	if err := p24.checkCredentials(); err != nil {
+		return nil, err
+	}
+
+	data = p24.transactionDefaults(data, opts...)
+	if err := validateInvariantParams(data); err != nil {
+		return nil, err
+	}
+
+	// The empty fields shadow, and so leave out, the ones of the params.
+	fields, err := json.Marshal(struct {
+		TransactionParams
+		SessionId string `json:"sessionId,omitempty"`
+		Amount    int    `json:"amount,omitempty"`
+	}{TransactionParams: data})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreparedTransaction{
+		p24:        p24,
+		merchantId: data.MerchantId,
+		posId:      data.PosId,
+		currency:   data.Currency,
+		fields:     fields,
+	}, nil
+}
+
+// Send registers the prepared transaction with sessionId and amount, like RegisterTransactionResult.
+func (t *PreparedTransaction) Send(sessionId string, amount int) (*RegisterResult, error) {
+	return t.SendContext(context.Background(), sessionId, amount)
+}
+
+// SendContext is like Send but the request is bound to ctx.
+func (t *PreparedTransaction) SendContext(ctx context.Context, sessionId string, amount int) (*RegisterResult, error) {
+	if err := validateSessionIdAndAmount(sessionId, amount); err != nil {
+		return nil, err
+	}
+
+	sign := calculateRegistrationSignature(t.p24.signer, sessionId, t.merchantId, amount, t.currency, t.p24.crc)
+
+	// Encoding a string cannot fail.
+	sessionIdJson, _ := json.Marshal(sessionId)
+	signJson, _ := json.Marshal(sign)
+
+	body := make([]byte, 0, len(t.fields)+len(sessionIdJson)+len(signJson)+40)
+	body = append(body, `{"sessionId":`...)
+	body = append(body, sessionIdJson...)
+	body = append(body, `,"amount":`...)
+	body = strconv.AppendInt(body, int64(amount), 10)
+	body = append(body, `,"sign":`...)
+	body = append(body, signJson...)
+	body = append(body, ',')
+	body = append(body, t.fields[1:]...)
+
+	return t.p24.register(ctx, t.posId, amount, json.RawMessage(body))
+}
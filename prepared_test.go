@@ -0,0 +1,89 @@
+package przelewy24
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestPreparedTransactionSendsRegistrationBody(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string]map[string]any{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("invalid body %s: %v", raw, err)
+		}
+		mu.Lock()
+		bodies[body["sessionId"].(string)] = body
+		mu.Unlock()
+		registerHandler(w, r)
+	}, Config{})
+
+	data := testTransaction("")
+	data.Amount = 0
+	data.Cart = []CartItem{{Name: "Doładowanie", Quantity: 1, Price: 1000}}
+	prepared, err := client.PrepareTransaction(data, WithPos(1000, 2000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Changes after preparing do not leak into the prepared transaction.
+	data.Cart[0].Name = "changed"
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := prepared.Send(fmt.Sprintf("top-up \"%d\"", i), 1000+i); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range 10 {
+		sessionId := fmt.Sprintf("top-up \"%d\"", i)
+		want := testTransaction(sessionId)
+		want.Amount = 1000 + i
+		want.Description = data.Description
+		want.Cart = []CartItem{{Name: "Doładowanie", Quantity: 1, Price: 1000}}
+		dryRun, err := client.RegisterTransactionDryRun(want, WithPos(1000, 2000))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var wantBody map[string]any
+		if err := json.Unmarshal(dryRun, &wantBody); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(bodies[sessionId], wantBody) {
+			t.Errorf("sent %v, want %v", bodies[sessionId], wantBody)
+		}
+	}
+}
+
+func TestPreparedTransactionValidatesSend(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	}, Config{})
+
+	prepared, err := client.PrepareTransaction(testTransaction(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prepared.Send("", 1000); err == nil {
+		t.Error("got no error for an empty sessionId")
+	}
+	if _, err := prepared.Send("order-1", 0); err == nil {
+		t.Error("got no error for a zero amount")
+	}
+}
@@ -0,0 +1,462 @@
+// Package przelewy24test provides an in-memory fake of the Przelewy24 API for tests
+// of code using the przelewy24 package, in the way net/http/httptest does for
+// net/http.
+package przelewy24test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/Wheeskeey/go-przelewy24"
+)
+
+// BaseURL is the address of the API simulated by Fake.
+const BaseURL = "https://fake.przelewy24.invalid"
+
+// Fake is a przelewy24.Client for tests of code using the przelewy24 package, e.g. a
+// checkout flow. It is the regular client, but its requests are handled in memory by
+// a simulation of the API instead of being sent anywhere. The simulation checks the
+// credentials and signs like Przelewy24 does and records the registered transactions,
+// which tests can then pay with Notification.
+//
+// The simulated API handles registering, verifying and looking up transactions,
+// refunds and TestAccess. Other endpoints respond with 404.
+type Fake struct {
+	przelewy24.Client
+
+	config przelewy24.Config
+
+	mu           sync.Mutex
+	transactions []*registeredTransaction
+	lastOrderId  int64
+}
+
+var _ przelewy24.Client = (*Fake)(nil)
+
+type registeredTransaction struct {
+	params  przelewy24.TransactionParams
+	token   string
+	orderId int64
+	status  przelewy24.TransactionStatus
+	refunds []przelewy24.RefundInfo
+}
+
+// NewFake returns a Fake using the credentials of config, which default to test
+// ones. The addresses, HTTP client and retries of config are ignored.
+func NewFake(config przelewy24.Config) *Fake {
+	if config.MerchantId == 0 {
+		config.MerchantId = 1
+	}
+	if config.PosId == 0 {
+		config.PosId = config.MerchantId
+	}
+	if config.ApiKey == "" {
+		config.ApiKey = "fake-api-key"
+	}
+	if config.Crc == "" {
+		config.Crc = "fake-crc"
+	}
+	if config.Signer == nil {
+		config.Signer = przelewy24.SHA384Signer{}
+	}
+
+	fake := &Fake{lastOrderId: 100000}
+
+	config.Environment = przelewy24.EnvironmentSandbox
+	config.BaseURL = BaseURL
+	config.HTTPClient = &http.Client{Transport: transport{fake.handler()}}
+	config.MaxRetries = 0
+	config.ProxyURL = ""
+
+	fake.config = config
+	fake.Client = przelewy24.New(config)
+
+	return fake
+}
+
+// Registered returns the params of all the transactions registered so far, with the
+// defaults of the client applied.
+func (f *Fake) Registered() []przelewy24.TransactionParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	registered := make([]przelewy24.TransactionParams, len(f.transactions))
+	for i, transaction := range f.transactions {
+		registered[i] = transaction.params
+	}
+
+	return registered
+}
+
+// TestingT is the part of testing.TB used by Fake.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertRegistered reports an error to t unless a transaction with want.SessionId was
+// registered with all the non-zero fields of want.
+func (f *Fake) AssertRegistered(t TestingT, want przelewy24.TransactionParams) {
+	t.Helper()
+
+	transaction := f.find(want.SessionId)
+	if transaction == nil {
+		t.Errorf("no transaction registered with sessionId %q", want.SessionId)
+		return
+	}
+
+	got := reflect.ValueOf(transaction.params)
+	wanted := reflect.ValueOf(want)
+	for i := range wanted.NumField() {
+		field := wanted.Type().Field(i)
+		if wanted.Field(i).IsZero() || !field.IsExported() {
+			continue
+		}
+		if !reflect.DeepEqual(got.Field(i).Interface(), wanted.Field(i).Interface()) {
+			t.Errorf("transaction %q registered with %s %v, want %v", want.SessionId, field.Name, got.Field(i).Interface(), wanted.Field(i).Interface())
+		}
+	}
+}
+
+// Notification pays the transaction registered with sessionId in full and returns the
+// signed notification Przelewy24 would send to its urlStatus.
+func (f *Fake) Notification(sessionId string) (przelewy24.NotificationParams, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	transaction := f.findLocked(sessionId)
+	if transaction == nil {
+		return przelewy24.NotificationParams{}, przelewy24.ErrTransactionNotFound
+	}
+	if transaction.status == przelewy24.TransactionStatusNoPayment {
+		transaction.status = przelewy24.TransactionStatusAdvance
+	}
+
+	params := transaction.params
+	notification := przelewy24.NotificationParams{
+		MerchantId:   params.MerchantId,
+		PosId:        params.PosId,
+		SessionId:    params.SessionId,
+		Amount:       params.Amount,
+		OriginAmount: params.Amount,
+		Currency:     params.Currency,
+		OrderId:      transaction.orderId,
+		MethodId:     params.Method,
+		Statement:    "p24-" + strconv.FormatInt(transaction.orderId, 10),
+	}
+	notification.Sign = notificationSign(f.config.Signer, notification.MerchantId, notification.PosId, notification.SessionId, notification.Amount, notification.OriginAmount, notification.Currency, notification.OrderId, notification.MethodId, notification.Statement, f.config.Crc)
+
+	return notification, nil
+}
+
+// NotificationRequest is like Notification, but returns the request Przelewy24 would
+// send with the notification, e.g. to be served by NotificationHandler.
+func (f *Fake) NotificationRequest(sessionId string) (*http.Request, error) {
+	notification, err := f.Notification(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return nil, err
+	}
+
+	target := f.find(sessionId).params.UrlStatus
+	if target == "" {
+		target = "/"
+	}
+	r := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	return r, nil
+}
+
+func (f *Fake) find(sessionId string) *registeredTransaction {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.findLocked(sessionId)
+}
+
+func (f *Fake) findLocked(sessionId string) *registeredTransaction {
+	for _, transaction := range f.transactions {
+		if transaction.params.SessionId == sessionId {
+			return transaction
+		}
+	}
+
+	return nil
+}
+
+func (f *Fake) findByOrderIdLocked(orderId int64) *registeredTransaction {
+	for _, transaction := range f.transactions {
+		if transaction.orderId == orderId {
+			return transaction
+		}
+	}
+
+	return nil
+}
+
+// transport passes requests to an http.Handler instead of sending them.
+type transport struct {
+	handler http.Handler
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+
+	return recorder.Result(), nil
+}
+
+// handler returns the simulation of the API.
+func (f *Fake) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/testAccess", f.handleTestAccess)
+	mux.HandleFunc("POST /api/v1/transaction/register", f.handleRegister)
+	mux.HandleFunc("PUT /api/v1/transaction/verify", f.handleVerify)
+	mux.HandleFunc("GET /api/v1/transaction/by/sessionId/{sessionId}", f.handleTransaction)
+	mux.HandleFunc("GET /api/v1/transaction/by/orderId/{orderId}", f.handleTransaction)
+	mux.HandleFunc("POST /api/v1/transaction/refund", f.handleRefund)
+	mux.HandleFunc("GET /api/v1/refund/by/orderId/{orderId}", f.handleRefunds)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posId, apiKey, ok := r.BasicAuth()
+		if !ok || !f.isPos(posId) || apiKey != f.config.ApiKey {
+			writeError(w, http.StatusUnauthorized, "Incorrect authentication")
+			return
+		}
+
+		if _, pattern := mux.Handler(r); pattern == "" {
+			writeError(w, http.StatusNotFound, "Not found")
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// isPos reports whether posId is the one of the configuration or one of its PosIds.
+func (f *Fake) isPos(posId string) bool {
+	if posId == strconv.Itoa(f.config.PosId) {
+		return true
+	}
+	for _, id := range f.config.PosIds {
+		if posId == strconv.Itoa(id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *Fake) handleTestAccess(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, http.StatusOK, true)
+}
+
+func (f *Fake) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var data struct {
+		przelewy24.TransactionParams
+		Sign string `json:"sign"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid input data")
+		return
+	}
+
+	expected := registrationSign(f.config.Signer, data.SessionId, data.MerchantId, data.Amount, data.Currency, f.config.Crc)
+	if !signsEqual(expected, data.Sign) {
+		writeError(w, http.StatusBadRequest, "Incorrect sign")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.findLocked(data.SessionId) != nil {
+		writeError(w, http.StatusBadRequest, "Transaction with this sessionId already exists")
+		return
+	}
+
+	f.lastOrderId++
+	transaction := &registeredTransaction{
+		params:  data.TransactionParams,
+		token:   przelewy24.NewSessionId(),
+		orderId: f.lastOrderId,
+	}
+	f.transactions = append(f.transactions, transaction)
+
+	writeResponse(w, http.StatusOK, map[string]string{"token": transaction.token})
+}
+
+func (f *Fake) handleVerify(w http.ResponseWriter, r *http.Request) {
+	var data struct {
+		SessionId string              `json:"sessionId"`
+		Amount    int                 `json:"amount"`
+		Currency  przelewy24.Currency `json:"currency"`
+		OrderId   int64               `json:"orderId"`
+		Sign      string              `json:"sign"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid input data")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	transaction := f.findByOrderIdLocked(data.OrderId)
+	expected := verificationSign(f.config.Signer, data.SessionId, data.OrderId, data.Amount, data.Currency, f.config.Crc)
+	switch {
+	case transaction == nil || transaction.params.SessionId != data.SessionId:
+		writeError(w, http.StatusBadRequest, "Transaction not found")
+	case transaction.status == przelewy24.TransactionStatusNoPayment:
+		writeError(w, http.StatusBadRequest, "Transaction not paid")
+	case transaction.params.Amount != data.Amount || transaction.params.Currency != data.Currency:
+		writeError(w, http.StatusBadRequest, "Incorrect amount or currency")
+	case !signsEqual(expected, data.Sign):
+		writeError(w, http.StatusBadRequest, "Incorrect sign")
+	default:
+		if transaction.status == przelewy24.TransactionStatusAdvance {
+			transaction.status = przelewy24.TransactionStatusPaid
+		}
+		writeResponse(w, http.StatusOK, map[string]string{"status": "success"})
+	}
+}
+
+func (f *Fake) handleTransaction(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var transaction *registeredTransaction
+	if sessionId := r.PathValue("sessionId"); sessionId != "" {
+		transaction = f.findLocked(sessionId)
+	} else if orderId, err := strconv.ParseInt(r.PathValue("orderId"), 10, 64); err == nil {
+		transaction = f.findByOrderIdLocked(orderId)
+	}
+	if transaction == nil {
+		writeError(w, http.StatusNotFound, "Transaction not found")
+		return
+	}
+
+	params := transaction.params
+	writeResponse(w, http.StatusOK, przelewy24.TransactionInfo{
+		Statement:      "p24-" + strconv.FormatInt(transaction.orderId, 10),
+		OrderId:        transaction.orderId,
+		SessionId:      params.SessionId,
+		Status:         transaction.status,
+		Amount:         params.Amount,
+		Currency:       params.Currency,
+		ClientEmail:    params.Email,
+		PaymentMethod:  params.Method,
+		Description:    params.Description,
+		ClientName:     params.Client,
+		ClientAddress:  params.Address,
+		ClientCity:     params.City,
+		ClientPostcode: params.Zip,
+	})
+}
+
+func (f *Fake) handleRefund(w http.ResponseWriter, r *http.Request) {
+	var data przelewy24.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid input data")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	results := make([]przelewy24.RefundResult, len(data.Refunds))
+	rejected := false
+	for i, refund := range data.Refunds {
+		results[i] = przelewy24.RefundResult{
+			OrderId:     refund.OrderId,
+			SessionId:   refund.SessionId,
+			Amount:      refund.Amount,
+			Description: refund.Description,
+			Status:      true,
+		}
+
+		transaction := f.findByOrderIdLocked(refund.OrderId)
+		switch {
+		case transaction == nil || transaction.params.SessionId != refund.SessionId:
+			results[i].Status, results[i].Message = false, "Transaction not found"
+		case transaction.status != przelewy24.TransactionStatusPaid:
+			results[i].Status, results[i].Message = false, "Transaction not paid"
+		case refund.Amount <= 0 || refund.Amount > transaction.params.Amount-refundedAmount(transaction):
+			results[i].Status, results[i].Message = false, "Incorrect amount"
+		default:
+			transaction.refunds = append(transaction.refunds, przelewy24.RefundInfo{
+				RequestId:   data.RequestId,
+				Description: refund.Description,
+				Status:      przelewy24.RefundStatusSuccess,
+				Amount:      refund.Amount,
+			})
+			continue
+		}
+		rejected = true
+	}
+
+	if rejected {
+		writeResult(w, http.StatusBadRequest, map[string]any{"error": results, "code": http.StatusBadRequest})
+		return
+	}
+
+	writeResponse(w, http.StatusCreated, results)
+}
+
+func refundedAmount(transaction *registeredTransaction) int {
+	refunded := 0
+	for _, refund := range transaction.refunds {
+		refunded += refund.Amount
+	}
+
+	return refunded
+}
+
+func (f *Fake) handleRefunds(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	orderId, _ := strconv.ParseInt(r.PathValue("orderId"), 10, 64)
+	transaction := f.findByOrderIdLocked(orderId)
+	if transaction == nil || len(transaction.refunds) == 0 {
+		writeError(w, http.StatusNotFound, "Refunds not found")
+		return
+	}
+
+	writeResponse(w, http.StatusOK, map[string]any{
+		"orderId":   transaction.orderId,
+		"sessionId": transaction.params.SessionId,
+		"amount":    transaction.params.Amount,
+		"currency":  transaction.params.Currency,
+		"refunds":   transaction.refunds,
+	})
+}
+
+func writeResponse(w http.ResponseWriter, status int, data any) {
+	writeResult(w, status, map[string]any{"data": data, "responseCode": 0})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeResult(w, status, map[string]any{"error": message, "code": status})
+}
+
+func writeResult(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// Encoding the maps and structs of the responses cannot fail.
+	_ = json.NewEncoder(w).Encode(body)
+}
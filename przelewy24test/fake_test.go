@@ -0,0 +1,156 @@
+package przelewy24test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Wheeskeey/go-przelewy24"
+)
+
+func testTransaction(sessionId string) przelewy24.TransactionParams {
+	return przelewy24.TransactionParams{
+		SessionId:   sessionId,
+		Amount:      1000,
+		Currency:    przelewy24.CurrencyPLN,
+		Description: "Zamówienie <1> & więcej",
+		Email:       "jan@example.com",
+		Country:     przelewy24.CountryPoland,
+		UrlReturn:   "https://shop.example.com/return?order=1",
+	}
+}
+
+func TestFakeAcceptsClientSigns(t *testing.T) {
+	fake := NewFake(przelewy24.Config{})
+	sessionId := "zamówienie/1 \"ą\""
+
+	if _, err := fake.RegisterTransaction(testTransaction(sessionId)); err != nil {
+		t.Fatalf("registration signed by the client rejected: %v", err)
+	}
+	fake.AssertRegistered(t, przelewy24.TransactionParams{SessionId: sessionId, Amount: 1000})
+
+	notification, err := fake.Notification(sessionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fake.VerifyNotificationSignature(notification) {
+		t.Error("client rejects the sign of the notification")
+	}
+
+	result, err := fake.VerifyTransaction(notification)
+	if err != nil {
+		t.Fatalf("verification signed by the client rejected: %v", err)
+	}
+	if !result.Verified() {
+		t.Errorf("got status %q, want verified", result.Status)
+	}
+
+	info, err := fake.GetTransactionBySessionId(sessionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Status != przelewy24.TransactionStatusPaid {
+		t.Errorf("got status %v, want paid", info.Status)
+	}
+
+	if _, err := fake.RefundBySessionId(sessionId, 400, "Zwrot"); err != nil {
+		t.Fatal(err)
+	}
+	refunds, err := fake.ListRefunds(info.OrderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refunds) != 1 || refunds[0].Amount != 400 {
+		t.Errorf("got refunds %+v", refunds)
+	}
+}
+
+func TestFakeRejectsForeignCredentials(t *testing.T) {
+	fake := NewFake(przelewy24.Config{})
+
+	tests := []struct {
+		name   string
+		apiKey string
+		crc    string
+		check  func(error) bool
+	}{
+		{"crc", fake.config.ApiKey, "other-crc", func(err error) bool {
+			var apiErr *przelewy24.APIError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest
+		}},
+		{"apiKey", "other-api-key", fake.config.Crc, func(err error) bool {
+			return errors.Is(err, przelewy24.ErrUnauthorized)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := przelewy24.New(przelewy24.Config{
+				MerchantId: fake.config.MerchantId,
+				PosId:      fake.config.PosId,
+				ApiKey:     tt.apiKey,
+				Crc:        tt.crc,
+				BaseURL:    BaseURL,
+				HTTPClient: &http.Client{Transport: transport{fake.handler()}},
+			})
+
+			if _, err := client.RegisterTransaction(testTransaction("order-1")); !tt.check(err) {
+				t.Errorf("got %v", err)
+			}
+			if len(fake.Registered()) != 0 {
+				t.Error("transaction registered")
+			}
+		})
+	}
+}
+
+func TestNotificationOfUnknownTransaction(t *testing.T) {
+	if _, err := NewFake(przelewy24.Config{}).Notification("order-1"); !errors.Is(err, przelewy24.ErrTransactionNotFound) {
+		t.Errorf("got %v, want %v", err, przelewy24.ErrTransactionNotFound)
+	}
+}
+
+// The wants are the sha384 vectors of signature_test.go in the przelewy24 package, of the
+// payloads in the comments.
+func TestSigns(t *testing.T) {
+	const crc = "a1b2c3d4e5f6a7b8"
+	signer := przelewy24.SHA384Signer{}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		// {"sessionId":"order-1","merchantId":11111,"amount":1000,"currency":"PLN","crc":"a1b2c3d4e5f6a7b8"}
+		{
+			"registration",
+			registrationSign(signer, "order-1", 11111, 1000, przelewy24.CurrencyPLN, crc),
+			"77135306ca8d996f3b3bf10b64487d3a0002139904abb427c921ca4fff15ffa411fb18af7bc1f215c1748d51faf7137f",
+		},
+		// {"sessionId":"zamówienie \"1\"/ą","merchantId":1000,"amount":1000,"currency":"PLN","crc":"test-crc"}
+		{
+			"registration of a non-ASCII sessionId",
+			registrationSign(signer, `zamówienie "1"/ą`, 1000, 1000, przelewy24.CurrencyPLN, "test-crc"),
+			"b7b3df5d22cdacc68a9745661958c20816b46c58e97e54e0703e35bd2248c8499295d9ddef375cd01365700b346a7860",
+		},
+		// {"sessionId":"order-1","orderId":312345678,"amount":600,"currency":"PLN","crc":"a1b2c3d4e5f6a7b8"}
+		{
+			"verification",
+			verificationSign(signer, "order-1", 312345678, 600, przelewy24.CurrencyPLN, crc),
+			"01de4ff36af4e4f33cbb5e6a1117fef995ff86167a9a66ea68fc004dabe8552e0414d32bdbe842afef70ed2f5e73f663",
+		},
+		// {"merchantId":11111,"posId":11111,"sessionId":"order-1","amount":1000,"originAmount":1000,"currency":"PLN",
+		// "orderId":312345678,"methodId":25,"statement":"p24-A1-B2-C3","crc":"a1b2c3d4e5f6a7b8"}
+		{
+			"notification",
+			notificationSign(signer, 11111, 11111, "order-1", 1000, 1000, przelewy24.CurrencyPLN, 312345678, 25, "p24-A1-B2-C3", crc),
+			"8a47713484e9659638fd0343f470c89d2ce58fb75b87cf4297a25b4aaf81ce6a84fbf2ec64ce92ce3661d34b934873b1",
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s sign = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
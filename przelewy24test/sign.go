@@ -0,0 +1,65 @@
+package przelewy24test
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+
+	"github.com/Wheeskeey/go-przelewy24"
+)
+
+// The signs are made here from structs of the documented fields rather than by the
+// przelewy24 package, so that the simulation also catches a client signing the wrong
+// payload or encoding it wrongly.
+
+func registrationSign(signer przelewy24.Signer, sessionId string, merchantId int, amount int, currency przelewy24.Currency, crc string) string {
+	return sign(signer, struct {
+		SessionId  string              `json:"sessionId"`
+		MerchantId int                 `json:"merchantId"`
+		Amount     int                 `json:"amount"`
+		Currency   przelewy24.Currency `json:"currency"`
+		Crc        string              `json:"crc"`
+	}{sessionId, merchantId, amount, currency, crc})
+}
+
+func verificationSign(signer przelewy24.Signer, sessionId string, orderId int64, amount int, currency przelewy24.Currency, crc string) string {
+	return sign(signer, struct {
+		SessionId string              `json:"sessionId"`
+		OrderId   int64               `json:"orderId"`
+		Amount    int                 `json:"amount"`
+		Currency  przelewy24.Currency `json:"currency"`
+		Crc       string              `json:"crc"`
+	}{sessionId, orderId, amount, currency, crc})
+}
+
+func notificationSign(signer przelewy24.Signer, merchantId int, posId int, sessionId string, amount int, originAmount int, currency przelewy24.Currency, orderId int64, methodId int, statement string, crc string) string {
+	return sign(signer, struct {
+		MerchantId   int                 `json:"merchantId"`
+		PosId        int                 `json:"posId"`
+		SessionId    string              `json:"sessionId"`
+		Amount       int                 `json:"amount"`
+		OriginAmount int                 `json:"originAmount"`
+		Currency     przelewy24.Currency `json:"currency"`
+		OrderId      int64               `json:"orderId"`
+		MethodId     int                 `json:"methodId"`
+		Statement    string              `json:"statement"`
+		Crc          string              `json:"crc"`
+	}{merchantId, posId, sessionId, amount, originAmount, currency, orderId, methodId, statement, crc})
+}
+
+func signsEqual(expected string, received string) bool {
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(received)) == 1
+}
+
+// sign signs the JSON of fields, a struct of the signed fields in their order. HTML
+// characters are left unescaped, as Przelewy24 encodes with JSON_UNESCAPED_UNICODE and
+// JSON_UNESCAPED_SLASHES.
+func sign(signer przelewy24.Signer, fields any) string {
+	var payload bytes.Buffer
+	encoder := json.NewEncoder(&payload)
+	encoder.SetEscapeHTML(false)
+	// Encoding strings and integers cannot fail.
+	_ = encoder.Encode(fields)
+
+	return signer.Sign(bytes.TrimSuffix(payload.Bytes(), []byte("\n")))
+}
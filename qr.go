@@ -0,0 +1,708 @@
+package przelewy24
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// QRLevel is the error correction level of a QR code: the higher it is, the more
+// of the code can be damaged or covered while it still scans, but the denser it is.
+// The zero value stands for QRLevelMedium.
+type QRLevel int
+
+const (
+	QRLevelLow QRLevel = iota + 1
+	QRLevelMedium
+	QRLevelQuartile
+	QRLevelHigh
+)
+
+// QRFormat is the image format of a QR code.
+type QRFormat string
+
+const (
+	QRFormatPNG QRFormat = "png"
+	QRFormatSVG QRFormat = "svg"
+)
+
+// QROptions describe the QR code returned by RegisterTransactionQR. The zero value
+// is a 256 pixels wide PNG with QRLevelMedium error correction.
+type QROptions struct {
+	Format QRFormat
+	// Size is the width and height of the image in pixels, including the margin. PNG
+	// images are rounded down to a whole number of pixels per module.
+	Size  int
+	Level QRLevel
+}
+
+// QRCode is an image of a QR code.
+type QRCode struct {
+	Image       []byte
+	ContentType string
+}
+
+var errQRTooLong = errors.New("content too long for a QR code")
+
+// QRRegisterResult is a registered transaction along with a QR code of its url.
+type QRRegisterResult struct {
+	RegisterResult
+	QR *QRCode
+}
+
+// RegisterTransactionQR is like RegisterTransactionResult, but also returns a QR code
+// of the url of the payment page described by qr, e.g. to be shown at a counter or a
+// kiosk for the customer to pay on their phone.
+func (p24 *p24) RegisterTransactionQR(data TransactionParams, qr QROptions, opts ...TransactionOption) (*QRRegisterResult, error) {
+	return p24.RegisterTransactionQRContext(context.Background(), data, qr, opts...)
+}
+
+// RegisterTransactionQRContext is like RegisterTransactionQR but the request is bound to ctx.
+func (p24 *p24) RegisterTransactionQRContext(ctx context.Context, data TransactionParams, qr QROptions, opts ...TransactionOption) (*QRRegisterResult, error) {
+	// Check the options first, not to register a transaction that cannot be shown.
+	if _, err := qr.normalized(); err != nil {
+		return nil, err
+	}
+
+	result, err := p24.RegisterTransactionResultContext(ctx, data, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := EncodeQR(result.Url, qr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QRRegisterResult{RegisterResult: *result, QR: code}, nil
+}
+
+// normalized returns the options with the defaults applied, or an error for invalid ones.
+func (o QROptions) normalized() (QROptions, error) {
+	if o.Size == 0 {
+		o.Size = 256
+	}
+	if o.Level == 0 {
+		o.Level = QRLevelMedium
+	}
+	if o.Format == "" {
+		o.Format = QRFormatPNG
+	}
+
+	if o.Size < 0 {
+		return o, fmt.Errorf("invalid QR code size %d", o.Size)
+	}
+	if o.Level < QRLevelLow || o.Level > QRLevelHigh {
+		return o, fmt.Errorf("invalid QR code level %d", o.Level)
+	}
+	if o.Format != QRFormatPNG && o.Format != QRFormatSVG {
+		return o, fmt.Errorf("unsupported QR code format %q", o.Format)
+	}
+
+	return o, nil
+}
+
+// EncodeQR returns a QR code of content, e.g. the url of a payment page.
+func EncodeQR(content string, options QROptions) (*QRCode, error) {
+	options, err := options.normalized()
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err := encodeQRModules([]byte(content), options.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Format == QRFormatSVG {
+		return &QRCode{Image: renderQRSVG(modules, options.Size), ContentType: "image/svg+xml"}, nil
+	}
+
+	img, err := renderQRPNG(modules, options.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QRCode{Image: img, ContentType: "image/png"}, nil
+}
+
+// qrQuietZone is the width of the margin around a QR code, in modules.
+const qrQuietZone = 4
+
+func renderQRPNG(modules [][]bool, size int) ([]byte, error) {
+	width := len(modules) + 2*qrQuietZone
+	scale := max(size/width, 1)
+
+	img := image.NewGray(image.Rect(0, 0, width*scale, width*scale))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for dy := range scale {
+				for dx := range scale {
+					img.SetGray((x+qrQuietZone)*scale+dx, (y+qrQuietZone)*scale+dy, color.Gray{})
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func renderQRSVG(modules [][]bool, size int) []byte {
+	width := len(modules) + 2*qrQuietZone
+
+	var path strings.Builder
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d,%dh1v1h-1z", x+qrQuietZone, y+qrQuietZone)
+			}
+		}
+	}
+
+	return fmt.Appendf(nil, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+		`<rect width="100%%" height="100%%" fill="#fff"/><path d="%s" fill="#000"/></svg>`,
+		size, size, width, width, path.String())
+}
+
+// The tables below are indexed by the error correction level, see qrLevelIndex,
+// and by the version of the code.
+var (
+	qrEccCodewordsPerBlock = [4][41]int{
+		{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	}
+	qrEccBlocks = [4][41]int{
+		{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+		{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+		{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+		{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+	}
+)
+
+// qrLevelIndex maps a QRLevel to the row of the tables above (L, M, Q, H).
+var qrLevelIndex = map[QRLevel]int{QRLevelLow: 0, QRLevelMedium: 1, QRLevelQuartile: 2, QRLevelHigh: 3}
+
+// qrLevelFormatBits are the bits identifying a QRLevel in the format information.
+var qrLevelFormatBits = map[QRLevel]int{QRLevelLow: 1, QRLevelMedium: 0, QRLevelQuartile: 3, QRLevelHigh: 2}
+
+// qrCode is a QR code being drawn. Modules are indexed by row, then column.
+type qrCode struct {
+	version    int
+	level      QRLevel
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// encodeQRModules encodes data in byte mode in the smallest version of a QR code
+// that fits it, and returns its modules, true being dark.
+func encodeQRModules(data []byte, level QRLevel) ([][]bool, error) {
+	return encodeQRSymbol(data, level, -1)
+}
+
+// encodeQRSymbol is like encodeQRModules, but applies mask, or the one with the lowest
+// penalty when mask is negative.
+func encodeQRSymbol(data []byte, level QRLevel, mask int) ([][]bool, error) {
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if 4+qrCharCountBits(v)+8*len(data) <= qrDataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errQRTooLong
+	}
+
+	capacity := qrDataCodewords(version, level) * 8
+	var bits qrBitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(data), qrCharCountBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i/8] |= 1 << (7 - i%8)
+		}
+	}
+
+	qr := newQRCode(version, level)
+	qr.drawFunctionPatterns()
+	qr.drawCodewords(qr.addEccAndInterleave(codewords))
+
+	if mask < 0 {
+		bestPenalty := -1
+		for candidate := range 8 {
+			qr.applyMask(candidate)
+			qr.drawFormatBits(candidate)
+			if penalty := qr.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+				mask, bestPenalty = candidate, penalty
+			}
+			// Masking twice restores the modules.
+			qr.applyMask(candidate)
+		}
+	}
+	qr.applyMask(mask)
+	qr.drawFormatBits(mask)
+
+	return qr.modules, nil
+}
+
+func newQRCode(version int, level QRLevel) *qrCode {
+	size := version*4 + 17
+	qr := &qrCode{
+		version:    version,
+		level:      level,
+		size:       size,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+	for i := range size {
+		qr.modules[i] = make([]bool, size)
+		qr.isFunction[i] = make([]bool, size)
+	}
+
+	return qr
+}
+
+// qrCharCountBits is the length of the character count of a byte mode segment.
+func qrCharCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+
+	return 16
+}
+
+// qrRawDataModules is the number of modules of a version available for data and its error correction.
+func qrRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		alignments := version/7 + 2
+		result -= (25*alignments-10)*alignments - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+
+	return result
+}
+
+// qrDataCodewords is the number of data codewords of a version and level.
+func qrDataCodewords(version int, level QRLevel) int {
+	i := qrLevelIndex[level]
+
+	return qrRawDataModules(version)/8 - qrEccCodewordsPerBlock[i][version]*qrEccBlocks[i][version]
+}
+
+type qrBitBuffer []bool
+
+func (b *qrBitBuffer) append(value int, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 == 1)
+	}
+}
+
+func (qr *qrCode) setFunction(x int, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.isFunction[y][x] = true
+}
+
+func (qr *qrCode) drawFunctionPatterns() {
+	for i := range qr.size {
+		qr.setFunction(6, i, i%2 == 0)
+		qr.setFunction(i, 6, i%2 == 0)
+	}
+
+	qr.drawFinderPattern(3, 3)
+	qr.drawFinderPattern(qr.size-4, 3)
+	qr.drawFinderPattern(3, qr.size-4)
+
+	positions := qr.alignmentPatternPositions()
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// The corners taken by finder patterns.
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			qr.drawAlignmentPattern(x, y)
+		}
+	}
+
+	// Reserve the format information, it is drawn along with the mask.
+	qr.drawFormatBits(0)
+	qr.drawVersion()
+}
+
+func (qr *qrCode) drawFinderPattern(x int, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= qr.size || yy < 0 || yy >= qr.size {
+				continue
+			}
+			distance := max(absInt(dx), absInt(dy))
+			qr.setFunction(xx, yy, distance != 2 && distance != 4)
+		}
+	}
+}
+
+func (qr *qrCode) drawAlignmentPattern(x int, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			qr.setFunction(x+dx, y+dy, max(absInt(dx), absInt(dy)) != 1)
+		}
+	}
+}
+
+func (qr *qrCode) alignmentPatternPositions() []int {
+	if qr.version == 1 {
+		return nil
+	}
+
+	count := qr.version/7 + 2
+	step := (qr.version*8 + count*3 + 5) / (count*4 - 4) * 2
+	positions := make([]int, count)
+	positions[0] = 6
+	for i, position := count-1, qr.size-7; i >= 1; i, position = i-1, position-step {
+		positions[i] = position
+	}
+
+	return positions
+}
+
+// qrFormatBits returns the 15 bits of format information of level and mask.
+func qrFormatBits(level QRLevel, mask int) int {
+	data := qrLevelFormatBits[level]<<3 | mask
+	remainder := data
+	for range 10 {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+
+	return (data<<10 | remainder) ^ 0x5412
+}
+
+func (qr *qrCode) drawFormatBits(mask int) {
+	bits := qrFormatBits(qr.level, mask)
+
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		qr.setFunction(8, i, bit(i))
+	}
+	qr.setFunction(8, 7, bit(6))
+	qr.setFunction(8, 8, bit(7))
+	qr.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		qr.setFunction(qr.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunction(8, qr.size-15+i, bit(i))
+	}
+	// The dark module.
+	qr.setFunction(8, qr.size-8, true)
+}
+
+// qrVersionBits returns the 18 bits of version information of version.
+func qrVersionBits(version int) int {
+	remainder := version
+	for range 12 {
+		remainder = (remainder << 1) ^ ((remainder >> 11) * 0x1F25)
+	}
+
+	return version<<12 | remainder
+}
+
+func (qr *qrCode) drawVersion() {
+	if qr.version < 7 {
+		return
+	}
+
+	bits := qrVersionBits(qr.version)
+	for i := range 18 {
+		dark := (bits>>i)&1 == 1
+		a, b := qr.size-11+i%3, i/3
+		qr.setFunction(a, b, dark)
+		qr.setFunction(b, a, dark)
+	}
+}
+
+// addEccAndInterleave splits data into blocks, appends the error correction codewords
+// to every block and interleaves them.
+func (qr *qrCode) addEccAndInterleave(data []byte) []byte {
+	level := qrLevelIndex[qr.level]
+	blockCount := qrEccBlocks[level][qr.version]
+	eccLength := qrEccCodewordsPerBlock[level][qr.version]
+	rawCodewords := qrRawDataModules(qr.version) / 8
+	shortBlocks := blockCount - rawCodewords%blockCount
+	shortBlockLength := rawCodewords / blockCount
+
+	divisor := reedSolomonDivisor(eccLength)
+	blocks := make([][]byte, blockCount)
+	for i, k := 0, 0; i < blockCount; i++ {
+		length := shortBlockLength - eccLength
+		if i >= shortBlocks {
+			length++
+		}
+		block := append([]byte(nil), data[k:k+length]...)
+		k += length
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < shortBlocks {
+			// Padding, so that all the blocks have the same length; skipped below.
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLength-eccLength || j >= shortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+
+	return result
+}
+
+// drawCodewords fills the modules that are not function patterns with data, in the zigzag order.
+func (qr *qrCode) drawCodewords(data []byte) {
+	i := 0
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// Skip the vertical timing pattern.
+			right = 5
+		}
+		for vertical := range qr.size {
+			for j := range 2 {
+				x := right - j
+				y := vertical
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vertical
+				}
+				if !qr.isFunction[y][x] && i < len(data)*8 {
+					qr.modules[y][x] = (data[i>>3]>>(7-i&7))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by mask. Applying it again undoes it.
+func (qr *qrCode) applyMask(mask int) {
+	for y := range qr.size {
+		for x := range qr.size {
+			if qr.isFunction[y][x] {
+				continue
+			}
+
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the code is to scan, the mask giving the lowest one is used.
+func (qr *qrCode) penalty() int {
+	penalty := 0
+
+	// Runs of modules of the same color, and patterns resembling finder patterns.
+	line := make([]bool, qr.size)
+	for _, horizontal := range []bool{true, false} {
+		for i := range qr.size {
+			for j := range qr.size {
+				if horizontal {
+					line[j] = qr.modules[i][j]
+				} else {
+					line[j] = qr.modules[j][i]
+				}
+			}
+			penalty += qrLinePenalty(line)
+		}
+	}
+
+	// 2x2 blocks of modules of the same color.
+	for y := 0; y < qr.size-1; y++ {
+		for x := 0; x < qr.size-1; x++ {
+			dark := qr.modules[y][x]
+			if dark == qr.modules[y][x+1] && dark == qr.modules[y+1][x] && dark == qr.modules[y+1][x+1] {
+				penalty += 3
+			}
+		}
+	}
+
+	// Balance of dark and light modules.
+	dark := 0
+	for _, row := range qr.modules {
+		for _, module := range row {
+			if module {
+				dark++
+			}
+		}
+	}
+	total := qr.size * qr.size
+	k := (absInt(dark*20-total*10)+total-1)/total - 1
+	penalty += k * 10
+
+	return penalty
+}
+
+// qrFinderLike is the 1:1:3:1:1 pattern of a finder, to be avoided next to 4 light modules.
+var qrFinderLike = []bool{true, false, true, true, true, false, true}
+
+func qrLinePenalty(line []bool) int {
+	penalty := 0
+
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			penalty += 3 + run - 5
+		}
+		run = 1
+	}
+
+	for i := 0; i+len(qrFinderLike) <= len(line); i++ {
+		if !qrMatches(line[i:], qrFinderLike) {
+			continue
+		}
+		end := i + len(qrFinderLike)
+		if qrLight(line, i-4, i) || qrLight(line, end, end+4) {
+			penalty += 40
+		}
+	}
+
+	return penalty
+}
+
+func qrMatches(line []bool, pattern []bool) bool {
+	for i, module := range pattern {
+		if line[i] != module {
+			return false
+		}
+	}
+
+	return true
+}
+
+// qrLight reports whether the modules of line from start to end are light, treating
+// the modules outside of the code as light.
+func qrLight(line []bool, start int, end int) bool {
+	for i := start; i < end; i++ {
+		if i >= 0 && i < len(line) && line[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reedSolomonDivisor returns the generator polynomial of the given degree, without
+// its leading coefficient, highest power first.
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for range degree {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords of data.
+func reedSolomonRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range divisor {
+			result[i] ^= gfMultiply(coefficient, factor)
+		}
+	}
+
+	return result
+}
+
+// gfMultiply multiplies x and y in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x byte, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+
+	return byte(z)
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
@@ -0,0 +1,195 @@
+package przelewy24
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// The golden symbols in testdata/qr were made by an independent encoder (Kazuhiko
+// Arase's QRCode for JavaScript) with the same version, level and mask, one row per
+// line, '#' being dark.
+func TestEncodeQRSymbolGolden(t *testing.T) {
+	tests := []struct {
+		golden  string
+		content string
+		level   QRLevel
+		mask    int
+	}{
+		{"1-L", "HELLO WORLD", QRLevelLow, 2},
+		{"5-H", "https://sandbox.przelewy24.pl/trnRequest/", QRLevelHigh, 5},
+		{"7-Q", strings.Repeat("https://secure.przelewy24.pl/trnRequest/", 2), QRLevelQuartile, 3},
+		{"10-M", strings.Repeat("https://secure.przelewy24.pl/trnRequest/0123456789", 4), QRLevelMedium, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			golden, err := os.ReadFile(filepath.Join("testdata", "qr", tt.golden+".txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			modules, err := encodeQRSymbol([]byte(tt.content), tt.level, tt.mask)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got strings.Builder
+			for _, row := range modules {
+				for _, dark := range row {
+					if dark {
+						got.WriteByte('#')
+					} else {
+						got.WriteByte('.')
+					}
+				}
+				got.WriteByte('\n')
+			}
+			if got.String() != string(golden) {
+				t.Errorf("symbol differs from the golden one, got:\n%s", got.String())
+			}
+		})
+	}
+}
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// HELLO WORLD in 1-M, from ISO/IEC 18004 annex I.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestQRFormatBits(t *testing.T) {
+	tests := []struct {
+		level QRLevel
+		mask  int
+		want  int
+	}{
+		{QRLevelLow, 0, 0b111011111000100},
+		{QRLevelMedium, 0, 0b101010000010010},
+		{QRLevelQuartile, 0, 0b011010101011111},
+		{QRLevelHigh, 0, 0b001011010001001},
+		{QRLevelMedium, 5, 0b100000011001110},
+		{QRLevelHigh, 7, 0b000100000111011},
+	}
+
+	for _, tt := range tests {
+		if got := qrFormatBits(tt.level, tt.mask); got != tt.want {
+			t.Errorf("level %d mask %d: got %015b, want %015b", tt.level, tt.mask, got, tt.want)
+		}
+	}
+}
+
+func TestQRVersionBits(t *testing.T) {
+	tests := map[int]int{
+		7:  0x07C94,
+		8:  0x085BC,
+		21: 0x15683,
+		40: 0x28C69,
+	}
+
+	for version, want := range tests {
+		if got := qrVersionBits(version); got != want {
+			t.Errorf("version %d: got %#x, want %#x", version, got, want)
+		}
+	}
+}
+
+func TestQRAlignmentPatternPositions(t *testing.T) {
+	tests := map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		32: {6, 34, 60, 86, 112, 138},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	}
+
+	for version, want := range tests {
+		if got := newQRCode(version, QRLevelMedium).alignmentPatternPositions(); !slices.Equal(got, want) {
+			t.Errorf("version %d: got %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestQRByteCapacity(t *testing.T) {
+	tests := []struct {
+		version int
+		level   QRLevel
+		want    int
+	}{
+		{1, QRLevelLow, 17},
+		{1, QRLevelMedium, 14},
+		{1, QRLevelQuartile, 11},
+		{1, QRLevelHigh, 7},
+		{10, QRLevelMedium, 213},
+		{40, QRLevelLow, 2953},
+		{40, QRLevelHigh, 1273},
+	}
+
+	for _, tt := range tests {
+		got := (qrDataCodewords(tt.version, tt.level)*8 - 4 - qrCharCountBits(tt.version)) / 8
+		if got != tt.want {
+			t.Errorf("version %d level %d: got %d bytes, want %d", tt.version, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeQRPicksSmallestVersion(t *testing.T) {
+	for _, n := range []int{17, 18} {
+		modules, err := encodeQRModules(bytes.Repeat([]byte("a"), n), QRLevelLow)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[int]int{17: 21, 18: 25}[n]; len(modules) != want {
+			t.Errorf("%d bytes: got a %d modules wide symbol, want %d", n, len(modules), want)
+		}
+	}
+
+	if _, err := encodeQRModules(bytes.Repeat([]byte("a"), 1274), QRLevelHigh); err != errQRTooLong {
+		t.Errorf("got %v, want %v", err, errQRTooLong)
+	}
+}
+
+func TestQRLevelsOrderedByStrength(t *testing.T) {
+	if !(QRLevelLow < QRLevelMedium && QRLevelMedium < QRLevelQuartile && QRLevelQuartile < QRLevelHigh) {
+		t.Fatal("levels are not ordered by strength")
+	}
+
+	zero, err := QROptions{}.normalized()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zero.Level != QRLevelMedium || zero.Size != 256 || zero.Format != QRFormatPNG {
+		t.Errorf("got %+v for the zero options", zero)
+	}
+}
+
+func TestEncodeQRInvalidOptions(t *testing.T) {
+	for _, options := range []QROptions{
+		{Size: -1},
+		{Level: QRLevelHigh + 1},
+		{Level: -1},
+		{Format: "gif"},
+	} {
+		if _, err := EncodeQR("https://example.com", options); err == nil {
+			t.Errorf("%+v: got no error", options)
+		}
+	}
+}
+
+func TestRegisterTransactionQRInvalidOptions(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	}, Config{})
+
+	if _, err := client.RegisterTransactionQR(testTransaction("qr-1"), QROptions{Format: "gif"}); err == nil {
+		t.Error("got no error")
+	}
+}
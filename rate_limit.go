@@ -0,0 +1,84 @@
+package przelewy24
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket holding up to burst tokens, refilled at rate tokens
+// per second. Calls finding it empty are queued: they take a token in advance and
+// wait until it becomes available.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a rateLimiter letting through rate calls per second, with
+// bursts of up to a second worth of them.
+func newRateLimiter(rate float64) *rateLimiter {
+	burst := max(rate, 1)
+
+	return &rateLimiter{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until the call may proceed or ctx is done. A call that gives up
+// returns its token, so it does not delay the calls queued after it.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.refillLocked()
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := sleepContext(ctx, delay); err != nil {
+		l.mu.Lock()
+		l.refillLocked()
+		l.tokens = min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// refillLocked adds the tokens accumulated since the last call. l.mu must be held.
+func (l *rateLimiter) refillLocked() {
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+}
+
+type rateLimiterKey struct{}
+
+// withRateLimiter returns ctx making every attempt of the requests sent with it,
+// including retries, wait for limiter. A nil limiter leaves ctx unchanged.
+func withRateLimiter(ctx context.Context, limiter *rateLimiter) context.Context {
+	if limiter == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, rateLimiterKey{}, limiter)
+}
+
+// waitRateLimit waits for the rate limiter of ctx, if there is one.
+func waitRateLimit(ctx context.Context) error {
+	if limiter, ok := ctx.Value(rateLimiterKey{}).(*rateLimiter); ok {
+		return limiter.wait(ctx)
+	}
+
+	return nil
+}
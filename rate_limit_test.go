@@ -0,0 +1,74 @@
+package przelewy24
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := newRateLimiter(10)
+
+	start := time.Now()
+	for range 10 {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("burst of 10 took %s, want no waiting", elapsed)
+	}
+
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("11th call after %s, want it to wait for a token", elapsed)
+	}
+}
+
+func TestRateLimiterCancelledWaitReturnsToken(t *testing.T) {
+	limiter := newRateLimiter(10)
+	for range 10 {
+		limiter.wait(context.Background())
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range 20 {
+		if err := limiter.wait(cancelled); err == nil {
+			t.Fatal("wait with a cancelled ctx succeeded")
+		}
+	}
+
+	// Without the tokens given back, the next call would wait for 2 seconds.
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx); err != nil {
+		t.Fatalf("wait after cancelled calls: %v", err)
+	}
+}
+
+func TestVerifyRetriesAreRateLimited(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":"Too many requests","code":429}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"status":"success"},"responseCode":0}`))
+	}, Config{MaxRetries: 2, RetryDelay: time.Millisecond, VerifyRateLimit: 1})
+
+	start := time.Now()
+	if _, err := client.VerifyTransaction(NotificationParams{SessionId: "order-1", Amount: 1000, Currency: CurrencyPLN, OrderId: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// One token is available at once, the 2 retries wait for a second each.
+	if elapsed := time.Since(start); elapsed < 1900*time.Millisecond {
+		t.Errorf("3 attempts took %s, want the retries to be throttled", elapsed)
+	}
+}
@@ -0,0 +1,67 @@
+package przelewy24
+
+import (
+	"context"
+	"errors"
+)
+
+// LocalOrder is the state of an order as recorded by the merchant, see Reconcile.
+type LocalOrder struct {
+	SessionId string
+	Paid      bool
+}
+
+// Discrepancy is an order whose state differs between the merchant and Przelewy24,
+// or that could not be checked, in which case Err is set.
+type Discrepancy struct {
+	SessionId   string
+	PaidLocally bool
+	PaidAtP24   bool
+	// Transaction is the transaction at Przelewy24, nil when there is none.
+	Transaction *TransactionInfo
+	Err         error
+}
+
+// Reconcile compares orders with their transactions at Przelewy24, fetched concurrently
+// with GetTransactionBySessionId, sending at most Config.Concurrency requests at once.
+// It returns the orders paid locally but not at Przelewy24 and vice versa, in the order
+// of orders. A transaction only counts as paid at Przelewy24 once it was verified, i.e.
+// with TransactionStatusPaid. Orders that could not be checked are returned as well,
+// with their error, and the returned error joins all of them.
+func (p24 *p24) Reconcile(orders []LocalOrder) ([]Discrepancy, error) {
+	return p24.ReconcileContext(context.Background(), orders)
+}
+
+// ReconcileContext is like Reconcile but the requests are bound to ctx.
+func (p24 *p24) ReconcileContext(ctx context.Context, orders []LocalOrder) ([]Discrepancy, error) {
+	checked := make([]Discrepancy, len(orders))
+	p24.forEachConcurrently(len(orders), func(i int) {
+		checked[i] = Discrepancy{
+			SessionId:   orders[i].SessionId,
+			PaidLocally: orders[i].Paid,
+		}
+
+		transaction, err := p24.GetTransactionBySessionIdContext(ctx, orders[i].SessionId)
+		if err != nil && !errors.Is(err, ErrTransactionNotFound) {
+			checked[i].Err = err
+			return
+		}
+		if transaction != nil {
+			checked[i].Transaction = transaction
+			checked[i].PaidAtP24 = transaction.Status == TransactionStatusPaid
+		}
+	})
+
+	var discrepancies []Discrepancy
+	var errs []error
+	for _, discrepancy := range checked {
+		if discrepancy.Err != nil {
+			errs = append(errs, discrepancy.Err)
+		} else if discrepancy.PaidLocally == discrepancy.PaidAtP24 {
+			continue
+		}
+		discrepancies = append(discrepancies, discrepancy)
+	}
+
+	return discrepancies, errors.Join(errs...)
+}
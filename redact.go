@@ -0,0 +1,54 @@
+package przelewy24
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// redactedConfig has the fields of Config but none of its methods, for formatting it.
+type redactedConfig Config
+
+// String formats the config with the apiKey, crc and proxy password redacted, so it can be logged safely.
+func (c Config) String() string {
+	return fmt.Sprintf("%+v", c.redacted())
+}
+
+// GoString is like String, for the %#v verb.
+func (c Config) GoString() string {
+	return "przelewy24.Config" + strings.TrimPrefix(fmt.Sprintf("%#v", c.redacted()), "przelewy24.redactedConfig")
+}
+
+func (c Config) redacted() redactedConfig {
+	c.ApiKey = redactSecret(c.ApiKey)
+	c.Crc = redactSecret(c.Crc)
+	if proxy, err := url.Parse(c.ProxyURL); err == nil {
+		c.ProxyURL = proxy.Redacted()
+	}
+
+	return redactedConfig(c)
+}
+
+// String describes the client with the apiKey and crc redacted, so it can be logged safely.
+func (p24 *p24) String() string {
+	return fmt.Sprintf("przelewy24.Client{Environment:%s BaseURL:%s MerchantId:%d PosId:%d ApiKey:%s Crc:%s}",
+		p24.environment, p24.baseURL, p24.merchantId, p24.posId, redactSecret(p24.apiKey), redactSecret(p24.crc))
+}
+
+// GoString is like String, for the %#v verb.
+func (p24 *p24) GoString() string {
+	return p24.String()
+}
+
+// redactSecret masks secret, leaving its last 4 characters visible when it is long
+// enough for that not to give much of it away.
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) < 12 {
+		return "****"
+	}
+
+	return "****" + secret[len(secret)-4:]
+}
@@ -0,0 +1,255 @@
+package przelewy24
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// RefundRequest is a batch of refunds. RequestId and RefundsUuid identify the batch,
+// so the API does not process it twice when it is sent again. Persist the request
+// before sending it to be able to resubmit it safely, e.g. after a crash.
+type RefundRequest struct {
+	RequestId   string       `json:"requestId"`
+	Refunds     []RefundItem `json:"refunds"`
+	RefundsUuid string       `json:"refundsUuid"`
+	UrlStatus   string       `json:"urlStatus,omitempty"`
+}
+
+// NewRefundRequest returns a RefundRequest of refunds with freshly generated RequestId and RefundsUuid.
+func NewRefundRequest(refunds ...RefundItem) RefundRequest {
+	return RefundRequest{
+		RequestId:   NewSessionId(),
+		Refunds:     refunds,
+		RefundsUuid: NewSessionId(),
+	}
+}
+
+type RefundItem struct {
+	OrderId     int64  `json:"orderId"`
+	SessionId   string `json:"sessionId"`
+	Amount      int    `json:"amount"`
+	Description string `json:"description,omitempty"`
+}
+
+// RefundResult is the outcome of a single line of a RefundRequest. Status reports
+// whether Przelewy24 accepted the line; for a rejected one, Message says why.
+type RefundResult struct {
+	OrderId     int64  `json:"orderId"`
+	SessionId   string `json:"sessionId"`
+	Amount      int    `json:"amount"`
+	Description string `json:"description"`
+	Status      bool   `json:"status"`
+	Message     string `json:"message"`
+}
+
+// RefundResponse holds the per-line results in Data on success. When some of
+// the lines are rejected the API reports all of them in Error instead, so unlike
+// other responses it does not embed BaseResponse.
+type RefundResponse struct {
+	Data         []RefundResult  `json:"data"`
+	ResponseCode int             `json:"responseCode"`
+	Error        json.RawMessage `json:"error"`
+	Code         int             `json:"code"`
+}
+
+// Refund requests refunds of the given transactions. The returned slice holds
+// the result of every line, including rejected ones, so a partially rejected batch
+// is not an error; one is returned only when the request as a whole failed, e.g. on
+// a network error or when the API rejects the batch without per-line results.
+// Missing RequestId and RefundsUuid are generated, but only identifiers set by the
+// caller allow resubmitting data safely.
+func (p24 *p24) Refund(data RefundRequest) ([]RefundResult, error) {
+	return p24.RefundContext(context.Background(), data)
+}
+
+// RefundContext is like Refund but the request is bound to ctx.
+func (p24 *p24) RefundContext(ctx context.Context, data RefundRequest) ([]RefundResult, error) {
+	if data.RequestId == "" {
+		data.RequestId = NewSessionId()
+	}
+	if data.RefundsUuid == "" {
+		data.RefundsUuid = NewSessionId()
+	}
+
+	url := p24.baseURL + "/api/v1/transaction/refund"
+
+	// Retrying is safe, as every attempt carries the same identifiers.
+	var respBody RefundResponse
+	resp, err := p24.sendIdempotentRequest(withAttemptTimeout(ctx, p24.refundTimeout), "POST", url, data, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == 200 || resp.StatusCode == 201 {
+		return respBody.Data, nil
+	}
+
+	var results []RefundResult
+	if json.Unmarshal(respBody.Error, &results) == nil && len(results) > 0 {
+		return results, nil
+	}
+
+	var message string
+	_ = json.Unmarshal(respBody.Error, &message)
+
+	return nil, resp.apiError(BaseResponse{ResponseCode: respBody.ResponseCode, Error: ResponseError{Message: message}, Code: respBody.Code})
+}
+
+// RefundBySessionId refunds amount of the transaction registered with sessionId, or
+// all of its remaining amount when amount is 0. The orderId is looked up with
+// GetTransactionBySessionId, so the returned error matches ErrTransactionNotFound when
+// there is no such transaction, and ErrAlreadyRefunded when the successful and pending
+// refunds of the transaction leave less than amount to refund. An amount larger than
+// that of a transaction not refunded yet is a ValidationError matching ErrInvalidAmount.
+func (p24 *p24) RefundBySessionId(sessionId string, amount int, description string) ([]RefundResult, error) {
+	return p24.RefundBySessionIdContext(context.Background(), sessionId, amount, description)
+}
+
+// RefundBySessionIdContext is like RefundBySessionId but the requests are bound to ctx.
+func (p24 *p24) RefundBySessionIdContext(ctx context.Context, sessionId string, amount int, description string) ([]RefundResult, error) {
+	if amount < 0 {
+		return nil, &ValidationError{Field: "amount", Message: fmt.Sprintf("must not be negative, got %d", amount), Err: ErrInvalidAmount}
+	}
+
+	transaction, err := p24.GetTransactionBySessionIdContext(ctx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := p24.getRefunds(ctx, transaction.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := transaction.Amount
+	for _, refund := range refunds {
+		if refund.Status != RefundStatusRejected {
+			remaining -= refund.Amount
+		}
+	}
+	if remaining <= 0 {
+		return nil, ErrAlreadyRefunded
+	}
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount > remaining {
+		if remaining == transaction.Amount {
+			return nil, &ValidationError{Field: "amount", Message: fmt.Sprintf("must be at most the %d of the transaction, got %d", transaction.Amount, amount), Err: ErrInvalidAmount}
+		}
+		return nil, ErrAlreadyRefunded
+	}
+
+	return p24.RefundContext(ctx, NewRefundRequest(RefundItem{
+		OrderId:     transaction.OrderId,
+		SessionId:   transaction.SessionId,
+		Amount:      amount,
+		Description: description,
+	}))
+}
+
+// RefundStatus is the state of a refund.
+type RefundStatus int
+
+const (
+	RefundStatusUnknown  RefundStatus = 0
+	RefundStatusSuccess  RefundStatus = 1
+	RefundStatusPending  RefundStatus = 3
+	RefundStatusRejected RefundStatus = 4
+)
+
+func (s RefundStatus) String() string {
+	switch s {
+	case RefundStatusSuccess:
+		return "success"
+	case RefundStatusPending:
+		return "pending"
+	case RefundStatusRejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// Final reports whether the refund will not change its status anymore.
+func (s RefundStatus) Final() bool {
+	return s == RefundStatusSuccess || s == RefundStatusRejected
+}
+
+// RefundInfo describes a single refund of a transaction.
+type RefundInfo struct {
+	BatchId     int          `json:"batchId"`
+	RequestId   string       `json:"requestId"`
+	Date        string       `json:"date"`
+	Login       string       `json:"login"`
+	Description string       `json:"description"`
+	Status      RefundStatus `json:"status"`
+	Amount      int          `json:"amount"`
+}
+
+type RefundsResponse struct {
+	Data struct {
+		OrderId   int64        `json:"orderId"`
+		SessionId string       `json:"sessionId"`
+		Amount    int          `json:"amount"`
+		Currency  Currency     `json:"currency"`
+		Refunds   []RefundInfo `json:"refunds"`
+	} `json:"data"`
+	BaseResponse
+}
+
+// GetRefundStatus returns the status of the refund of the transaction with orderId
+// requested with requestId (see RefundRequest.RequestId).
+func (p24 *p24) GetRefundStatus(orderId int64, requestId string) (RefundStatus, error) {
+	return p24.GetRefundStatusContext(context.Background(), orderId, requestId)
+}
+
+// GetRefundStatusContext is like GetRefundStatus but the request is bound to ctx.
+func (p24 *p24) GetRefundStatusContext(ctx context.Context, orderId int64, requestId string) (RefundStatus, error) {
+	refunds, err := p24.getRefunds(ctx, orderId)
+	if err != nil {
+		return RefundStatusUnknown, err
+	}
+
+	for _, refund := range refunds {
+		if refund.RequestId == requestId {
+			return refund.Status, nil
+		}
+	}
+
+	return RefundStatusUnknown, ErrRefundNotFound
+}
+
+// ListRefunds returns all refunds of the transaction with orderId, with their amounts,
+// statuses and dates. A transaction without refunds has none listed rather than the 404
+// the API answers with.
+func (p24 *p24) ListRefunds(orderId int64) ([]RefundInfo, error) {
+	return p24.ListRefundsContext(context.Background(), orderId)
+}
+
+// ListRefundsContext is like ListRefunds but the request is bound to ctx.
+func (p24 *p24) ListRefundsContext(ctx context.Context, orderId int64) ([]RefundInfo, error) {
+	return p24.getRefunds(ctx, orderId)
+}
+
+func (p24 *p24) getRefunds(ctx context.Context, orderId int64) ([]RefundInfo, error) {
+	endpoint := p24.baseURL + "/api/v1/refund/by/orderId/" + strconv.FormatInt(orderId, 10)
+
+	var respBody RefundsResponse
+	resp, err := p24.sendIdempotentRequest(ctx, "GET", endpoint, nil, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == 404 {
+		// The transaction was not refunded yet.
+		return nil, nil
+	}
+	if resp.StatusCode != 200 {
+		return nil, resp.apiError(respBody.BaseResponse)
+	}
+
+	return respBody.Data.Refunds, nil
+}
@@ -0,0 +1,106 @@
+package przelewy24
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// refundsHandler serves a transaction of 1000 with the given refunds, none meaning the
+// 404 the API answers with before the first refund, and accepts every refund request.
+func refundsHandler(t *testing.T, refunds []RefundInfo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/transaction/by/sessionId/order-1":
+			w.Write([]byte(`{"data":{"orderId":1,"sessionId":"order-1","status":2,"amount":1000,"currency":"PLN"},"responseCode":0}`))
+		case "/api/v1/refund/by/orderId/1":
+			if len(refunds) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":"Refunds not found","code":404}`))
+				return
+			}
+			body, _ := json.Marshal(map[string]any{"data": map[string]any{"orderId": 1, "sessionId": "order-1", "amount": 1000, "currency": "PLN", "refunds": refunds}, "responseCode": 0})
+			w.Write(body)
+		case "/api/v1/transaction/refund":
+			var data RefundRequest
+			if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+				t.Error(err)
+			}
+			results := make([]RefundResult, len(data.Refunds))
+			for i, refund := range data.Refunds {
+				results[i] = RefundResult{OrderId: refund.OrderId, SessionId: refund.SessionId, Amount: refund.Amount, Status: true}
+			}
+			body, _ := json.Marshal(map[string]any{"data": results, "responseCode": 0})
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func TestRefundBySessionId(t *testing.T) {
+	tests := []struct {
+		name       string
+		refunds    []RefundInfo
+		amount     int
+		wantAmount int
+		wantErr    error
+	}{
+		{"whole", nil, 0, 1000, nil},
+		{"part", nil, 400, 400, nil},
+		{"more than the transaction", nil, 1500, 0, ErrInvalidAmount},
+		{"rest", []RefundInfo{{Amount: 600, Status: RefundStatusSuccess}}, 0, 400, nil},
+		{"more than the rest", []RefundInfo{{Amount: 600, Status: RefundStatusSuccess}}, 500, 0, ErrAlreadyRefunded},
+		{"after a rejected refund", []RefundInfo{{Amount: 1000, Status: RefundStatusRejected}}, 1000, 1000, nil},
+		{"refunded", []RefundInfo{{Amount: 1000, Status: RefundStatusSuccess}}, 0, 0, ErrAlreadyRefunded},
+		{"refunded, with amount", []RefundInfo{{Amount: 1000, Status: RefundStatusSuccess}}, 100, 0, ErrAlreadyRefunded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, refundsHandler(t, tt.refunds), Config{})
+
+			results, err := client.RefundBySessionId("order-1", tt.amount, "Zwrot")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				var validationErr *ValidationError
+				if isValidation := errors.As(err, &validationErr); isValidation != (tt.wantErr == ErrInvalidAmount) {
+					t.Errorf("got %T, a ValidationError only for an invalid amount", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 || results[0].Amount != tt.wantAmount {
+				t.Errorf("got %+v, want a refund of %d", results, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func TestListRefundsWithoutRefunds(t *testing.T) {
+	client := newTestClient(t, refundsHandler(t, nil), Config{})
+
+	refunds, err := client.ListRefunds(1)
+	if err != nil || len(refunds) != 0 {
+		t.Errorf("got %v, %v, want no refunds", refunds, err)
+	}
+
+	if _, err := client.GetRefundStatus(1, "request-1"); !errors.Is(err, ErrRefundNotFound) {
+		t.Errorf("got %v, want %v", err, ErrRefundNotFound)
+	}
+}
+
+func TestListRefunds(t *testing.T) {
+	client := newTestClient(t, refundsHandler(t, []RefundInfo{{RequestId: "request-1", Amount: 600, Status: RefundStatusSuccess}}), Config{})
+
+	refunds, err := client.ListRefunds(1)
+	if err != nil || len(refunds) != 1 || refunds[0].Amount != 600 {
+		t.Errorf("got %+v, %v", refunds, err)
+	}
+}
@@ -0,0 +1,253 @@
+package przelewy24
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// response is a decoded response of the API. Its raw body is kept for error reporting.
+type response struct {
+	*http.Response
+	body []byte
+	// environment is the one the response came from, empty for a custom BaseURL.
+	environment Environment
+}
+
+// apiError returns an APIError describing resp, which was decoded into base.
+func (resp *response) apiError(base BaseResponse) *APIError {
+	return &APIError{
+		StatusCode:   resp.StatusCode,
+		ResponseCode: base.ResponseCode,
+		Code:         base.Code,
+		Message:      base.Error.Message,
+		Fields:       base.Error.Fields,
+		RequestId:    resp.Header.Get("X-Request-Id"),
+		Body:         resp.body,
+		Header:       resp.Header,
+		Hint:         resp.hint(),
+	}
+}
+
+// hint returns a likely cause of resp failing, if there is one.
+func (resp *response) hint() string {
+	if resp.StatusCode == http.StatusUnauthorized && resp.environment != "" {
+		return fmt.Sprintf("the posId and apiKey are not valid in the %s environment; "+
+			"sandbox and production accounts have different credentials, see CheckEnvironment", resp.environment)
+	}
+
+	return ""
+}
+
+// sendRequest sends payload as JSON (unless nil) and decodes the JSON response into respBody.
+func (p24 *p24) sendRequest(ctx context.Context, method string, url string, payload any, respBody any) (*response, error) {
+	return p24.sendRequestWithRetries(ctx, method, url, payload, respBody, 0)
+}
+
+// sendIdempotentRequest is like sendRequest, but retries transient failures up to
+// the configured number of times. It must not be used for requests that are not
+// safe to repeat, such as charges.
+func (p24 *p24) sendIdempotentRequest(ctx context.Context, method string, url string, payload any, respBody any) (*response, error) {
+	return p24.sendRequestWithRetries(ctx, method, url, payload, respBody, p24.maxRetries)
+}
+
+func (p24 *p24) sendRequestWithRetries(ctx context.Context, method string, url string, payload any, respBody any, maxRetries int) (*response, error) {
+	if err := p24.checkCredentials(); err != nil {
+		return nil, err
+	}
+
+	var payloadJson []byte
+	if raw, ok := payload.(json.RawMessage); ok {
+		// Already encoded, e.g. by a PreparedTransaction.
+		payloadJson = raw
+	} else if payload != nil {
+		var err error
+		payloadJson, err = json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := waitRateLimit(ctx); err != nil {
+			return nil, err
+		}
+
+		var body io.Reader
+		if payloadJson != nil {
+			body = bytes.NewReader(payloadJson)
+		}
+
+		// An attempt ends at the earlier of its timeout and the deadline of ctx.
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout := attemptTimeout(ctx, p24.timeout); timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+
+		req, err := http.NewRequestWithContext(attemptCtx, method, url, body)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		for name, values := range p24.headers {
+			for _, value := range values {
+				req.Header.Add(name, value)
+			}
+		}
+		if payloadJson != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", p24.userAgent)
+		req.Header.Set("Accept-Language", acceptLanguage(ctx, p24.acceptLanguage))
+		req.SetBasicAuth(strconv.Itoa(p24.posId), p24.apiKey)
+
+		resp, err := p24.httpClient.Do(req)
+		var respJson []byte
+		if err == nil {
+			respJson, err = readBody(resp)
+		}
+		cancel()
+		if p24.trace != nil {
+			p24.traceRequest(method, url, payloadJson, resp, respJson, err)
+		}
+
+		if attempt < maxRetries && p24.retryOn(resp, err) && ctx.Err() == nil {
+			if err := sleepContext(ctx, p24.retryDelay(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(respJson, respBody)
+		success := resp.StatusCode >= 200 && resp.StatusCode < 300
+		// An error response is still reported by its status, e.g. an empty 401, as the
+		// APIError the callers build from it.
+		if err != nil && success {
+			// Typically an HTML page of a proxy or of a maintenance break.
+			return nil, &ResponseFormatError{
+				StatusCode:  resp.StatusCode,
+				ContentType: resp.Header.Get("Content-Type"),
+				Body:        respJson,
+				Err:         err,
+			}
+		}
+		// Error responses are left to APIError, which keeps their whole body anyway.
+		if p24.strictDecoding && success {
+			decoder := json.NewDecoder(bytes.NewReader(respJson))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(respBody); err != nil {
+				return nil, fmt.Errorf("strict decoding of response with status %d: %w", resp.StatusCode, err)
+			}
+		}
+
+		result := &response{Response: resp, body: respJson}
+		if p24.baseURL == p24.environment.BaseURL() {
+			result.environment = p24.environment
+		}
+
+		return result, nil
+	}
+}
+
+type attemptTimeoutKey struct{}
+
+// withAttemptTimeout returns ctx making every attempt of the requests sent with it time
+// out after timeout instead of the configured Timeout. A zero timeout keeps the configured
+// one. Either way the deadline of ctx, if any, applies too, and whichever expires first
+// ends the attempt.
+func withAttemptTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	if timeout == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, attemptTimeoutKey{}, timeout)
+}
+
+// attemptTimeout returns the timeout of a single attempt of a request sent with ctx.
+func attemptTimeout(ctx context.Context, fallback time.Duration) time.Duration {
+	if timeout, ok := ctx.Value(attemptTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+
+	return fallback
+}
+
+type acceptLanguageKey struct{}
+
+// WithAcceptLanguage returns a copy of ctx making the calls bound to it request error
+// messages in language, e.g. the one of the customer, instead of Config.AcceptLanguage.
+func WithAcceptLanguage(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, acceptLanguageKey{}, language)
+}
+
+// acceptLanguage returns the Accept-Language of the requests sent with ctx.
+func acceptLanguage(ctx context.Context, fallback string) string {
+	if language, ok := ctx.Value(acceptLanguageKey{}).(string); ok && language != "" {
+		return language
+	}
+
+	return fallback
+}
+
+// readBody reads the whole body of resp and closes it.
+func readBody(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+// Trace describes a single request sent to the API, see Config.Trace.
+type Trace struct {
+	Method       string
+	Url          string
+	RequestBody  []byte
+	StatusCode   int
+	ResponseBody []byte
+	// Header holds the headers of the response, e.g. Retry-After or other rate limiting ones.
+	Header http.Header
+	// Err is the error that made the request fail before a response was read.
+	Err error
+}
+
+// traceRequest passes the request to the trace hook, with the apiKey and crc redacted from the bodies.
+func (p24 *p24) traceRequest(method string, url string, reqBody []byte, resp *http.Response, respBody []byte, err error) {
+	trace := Trace{
+		Method:       method,
+		Url:          url,
+		RequestBody:  p24.redact(reqBody),
+		ResponseBody: p24.redact(respBody),
+		Err:          err,
+	}
+	if resp != nil {
+		trace.StatusCode = resp.StatusCode
+		trace.Header = resp.Header
+	}
+
+	p24.trace(trace)
+}
+
+// cardDataFields matches the fields of CardData that must not leave the client, as
+// encoded by encoding/json.
+var cardDataFields = regexp.MustCompile(`"(cardNumber|cardDate|cvv)":"(?:[^"\\]|\\.)*"`)
+
+// redact masks the apiKey, the crc and card data in body.
+func (p24 *p24) redact(body []byte) []byte {
+	for _, secret := range []string{p24.apiKey, p24.crc} {
+		if secret != "" {
+			body = bytes.ReplaceAll(body, []byte(secret), []byte("[REDACTED]"))
+		}
+	}
+
+	return cardDataFields.ReplaceAll(body, []byte(`"$1":"[REDACTED]"`))
+}
@@ -0,0 +1,255 @@
+package przelewy24
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client of a test server calling handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc, config Config, opts ...ClientOption) *p24 {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config.BaseURL = server.URL
+	if config.MerchantId == 0 {
+		config.MerchantId = 1000
+	}
+	if config.PosId == 0 {
+		config.PosId = config.MerchantId
+	}
+	if config.ApiKey == "" {
+		config.ApiKey = "test-api-key"
+	}
+	if config.Crc == "" {
+		config.Crc = "test-crc"
+	}
+
+	return New(config, opts...)
+}
+
+func TestTraceRedactsCardData(t *testing.T) {
+	var traces []Trace
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"orderId":1,"sessionId":"s"},"responseCode":0}`))
+	}, Config{Trace: func(trace Trace) { traces = append(traces, trace) }})
+
+	_, err := client.ChargeCard("token", CardData{CardNumber: "4111111111111111", CardDate: "122030", Cvv: "123", ClientName: "Jan \"J\" Kowalski"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(traces) != 1 {
+		t.Fatalf("got %d traces, want 1", len(traces))
+	}
+	body := traces[0].RequestBody
+	for _, secret := range []string{"4111111111111111", "122030", `"123"`, "test-api-key"} {
+		if bytes.Contains(body, []byte(secret)) {
+			t.Errorf("trace body %s contains %s", body, secret)
+		}
+	}
+	want := `{"transactionToken":"token","cardNumber":"[REDACTED]","cardDate":"[REDACTED]","cvv":"[REDACTED]","clientName":"Jan \"J\" Kowalski"}`
+	if string(body) != want {
+		t.Errorf("trace body = %s, want %s", body, want)
+	}
+}
+
+// hang blocks until the client gives up on r, or for long enough to fail any test relying on timeouts.
+func hang(r *http.Request) {
+	// The server notices the client going away only once the body was read.
+	io.Copy(io.Discard, r.Body)
+
+	select {
+	case <-r.Context().Done():
+	case <-time.After(5 * time.Second):
+	}
+}
+
+func TestTimeoutWithoutContextDeadline(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		call   func(*p24) error
+	}{
+		{"Timeout", Config{Timeout: 50 * time.Millisecond}, func(client *p24) error {
+			_, err := client.TestAccessContext(context.Background())
+			return err
+		}},
+		{"RegisterTimeout", Config{Timeout: 5 * time.Second, RegisterTimeout: 50 * time.Millisecond}, func(client *p24) error {
+			_, err := client.RegisterTransactionContext(context.Background(), testTransaction("order-1"))
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				hang(r)
+			}, tt.config)
+
+			start := time.Now()
+			if err := tt.call(client); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("err = %v, want a deadline exceeded error", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("took %s, the %s was not applied", elapsed, tt.name)
+			}
+		})
+	}
+}
+
+func TestAttemptTimeoutWithinContextDeadline(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			hang(r)
+			return
+		}
+		w.Write([]byte(`{"data":true,"responseCode":0}`))
+	}, Config{Timeout: 50 * time.Millisecond, MaxRetries: 2, RetryDelay: time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	ok, err := client.TestAccessContext(ctx)
+	if err != nil || !ok {
+		t.Fatalf("TestAccess = %v, %v, want the retry to succeed", ok, err)
+	}
+	if n := attempts.Load(); n != 2 {
+		t.Errorf("got %d attempts, want 2", n)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %s, the hung attempt was not timed out", elapsed)
+	}
+}
+
+func TestEndpointTimeoutWithinContextDeadline(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		hang(r)
+	}, Config{Timeout: 5 * time.Second, RegisterTimeout: 50 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.RegisterTransactionContext(ctx, testTransaction("order-1"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want a deadline exceeded error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %s, RegisterTimeout was not applied", elapsed)
+	}
+}
+
+func TestContextDeadlineBeforeAttemptTimeout(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		hang(r)
+	}, Config{Timeout: 5 * time.Second, MaxRetries: 3})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.TestAccessContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want a deadline exceeded error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %s, the deadline of ctx was not applied", elapsed)
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{"unknown field", http.StatusOK, `{"data":{"orderId":1,"sessionId":"order-1","newField":1},"responseCode":0}`, nil},
+		{"not found", http.StatusNotFound, `{"error":"Transaction not found","code":404,"requestId":"abc"}`, ErrTransactionNotFound},
+		{"unauthorized", http.StatusUnauthorized, `{"error":"Incorrect authentication","code":401,"requestId":"abc"}`, ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}, Config{StrictDecoding: true})
+
+			_, err := client.GetTransactionBySessionId("order-1")
+			if err == nil {
+				t.Fatal("got no error")
+			}
+			if tt.wantErr == nil {
+				var apiErr *APIError
+				if errors.As(err, &apiErr) {
+					t.Errorf("got APIError %v for an unknown field", err)
+				}
+				return
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want an APIError matching %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUndecodableErrorResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		wantErr     error
+	}{
+		{"empty unauthorized", http.StatusUnauthorized, "", "", ErrUnauthorized},
+		{"HTML not found", http.StatusNotFound, "text/html", "<html><body>Not Found</body></html>", ErrTransactionNotFound},
+		{"HTML bad gateway", http.StatusBadGateway, "text/html", "<html><body>Bad Gateway</body></html>", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}, Config{})
+
+			_, err := client.GetTransactionBySessionId("order-1")
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Fatalf("got %v, want an APIError with status %d", err, tt.status)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUndecodableSuccessfulResponse(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Maintenance</body></html>"))
+	}, Config{})
+
+	_, err := client.GetTransactionBySessionId("order-1")
+	var formatErr *ResponseFormatError
+	if !errors.As(err, &formatErr) || formatErr.ContentType != "text/html" {
+		t.Errorf("got %v, want a ResponseFormatError", err)
+	}
+}
@@ -0,0 +1,48 @@
+package przelewy24
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// DefaultRetryOn is the default policy of which failures are retried, see WithRetryPolicy:
+// network errors, timeouts and responses with status 429 or 5xx, which may succeed when
+// repeated. Other responses, such as 400 for invalid params, are not retried.
+func DefaultRetryOn(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryDelay returns the delay before retry number attempt+1: the base delay doubled
+// for every previous retry, randomized to between half and the full value.
+func (p24 *p24) retryDelay(attempt int) time.Duration {
+	delay := p24.retryBase
+	for i := 0; i < attempt && delay < time.Minute; i++ {
+		delay *= 2
+	}
+	if delay > time.Minute {
+		delay = time.Minute
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
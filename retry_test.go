@@ -0,0 +1,193 @@
+package przelewy24
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyHandler answers the first failures requests with statuses in turn, 0 standing
+// for a network error, and the rest with next. It counts all the requests in attempts.
+func flakyHandler(attempts *atomic.Int32, failures int, statuses []int, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		attempt := int(attempts.Add(1)) - 1
+		if attempt >= failures {
+			next(w, r)
+			return
+		}
+
+		switch status := statuses[attempt%len(statuses)]; status {
+		case 0:
+			// A network error: the connection is closed without a response.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		default:
+			w.WriteHeader(status)
+			fmt.Fprintf(w, `{"error":%q,"code":%d}`, http.StatusText(status), status)
+		}
+	}
+}
+
+func TestRetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+	}{
+		{"5xx", []int{http.StatusServiceUnavailable, http.StatusBadGateway}},
+		{"429", []int{http.StatusTooManyRequests}},
+		{"network error", []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			client := newTestClient(t, flakyHandler(&attempts, 2, tt.statuses, registerHandler), Config{MaxRetries: 2, RetryDelay: 1})
+
+			if _, err := client.RegisterTransaction(testTransaction("order-1")); err != nil {
+				t.Fatal(err)
+			}
+			if got := attempts.Load(); got != 3 {
+				t.Errorf("got %d attempts, want 3", got)
+			}
+		})
+	}
+}
+
+func TestRetriesGiveUp(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, flakyHandler(&attempts, 10, []int{http.StatusServiceUnavailable}, registerHandler), Config{MaxRetries: 2, RetryDelay: 1})
+
+	_, err := client.RegisterTransaction(testTransaction("order-1"))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got %v, want an APIError with status 503", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("got %d attempts, want 3", got)
+	}
+}
+
+func TestDoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, flakyHandler(&attempts, 10, []int{http.StatusBadRequest}, registerHandler), Config{MaxRetries: 2, RetryDelay: 1})
+
+	if _, err := client.RegisterTransaction(testTransaction("order-1")); err == nil {
+		t.Error("got no error")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("got %d attempts, want 1", got)
+	}
+}
+
+func TestDoesNotRetryCharges(t *testing.T) {
+	charges := map[string]func(Client) error{
+		"ChargeByToken": func(c Client) error {
+			_, err := c.ChargeByToken("ref-1", testTransaction("order-1"))
+			return err
+		},
+		"ChargeCard": func(c Client) error {
+			_, err := c.ChargeCard("token", CardData{CardNumber: "4111111111111111", CardDate: "122030", Cvv: "123", ClientName: "Jan Kowalski"})
+			return err
+		},
+		"ChargeCardWith3DS": func(c Client) error {
+			_, err := c.ChargeCardWith3DS("token")
+			return err
+		},
+		"ChargeBlik": func(c Client) error {
+			_, err := c.ChargeBlik("token", "123456")
+			return err
+		},
+		"ChargeBlikByAlias": func(c Client) error {
+			_, err := c.ChargeBlikByAlias("token", BlikAlias{Value: "alias", Type: "UID"})
+			return err
+		},
+	}
+
+	for name, charge := range charges {
+		t.Run(name, func(t *testing.T) {
+			for _, status := range []int{0, http.StatusServiceUnavailable} {
+				var attempts atomic.Int32
+				// Registering the transaction of ChargeByToken is safe to retry, charging is not.
+				flaky := flakyHandler(&attempts, 10, []int{status}, registerHandler)
+				client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/api/v1/transaction/register" {
+						registerHandler(w, r)
+						return
+					}
+					flaky(w, r)
+				}, Config{MaxRetries: 3, RetryDelay: 1})
+
+				if err := charge(client); err == nil {
+					t.Errorf("status %d: got no error", status)
+				}
+				if got := attempts.Load(); got != 1 {
+					t.Errorf("status %d: got %d attempts, want 1", status, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, flakyHandler(&attempts, 1, []int{http.StatusBadRequest}, registerHandler), Config{})
+	WithRetryPolicy(1, 1, func(resp *http.Response, err error) bool {
+		return err == nil && resp.StatusCode == http.StatusBadRequest
+	})(client)
+
+	if _, err := client.RegisterTransaction(testTransaction("order-1")); err != nil {
+		t.Fatal(err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("got %d attempts, want 2", got)
+	}
+}
+
+func TestRetryWithNegativeDelay(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestClient(t, flakyHandler(&attempts, 1, []int{http.StatusServiceUnavailable}, registerHandler), Config{MaxRetries: 1, RetryDelay: -time.Second})
+
+	if _, err := client.RegisterTransaction(testTransaction("order-1")); err != nil {
+		t.Fatal(err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("got %d attempts, want 2", got)
+	}
+	if client.retryBase != 200*time.Millisecond {
+		t.Errorf("retryBase = %v, want the default", client.retryBase)
+	}
+	if delay := (&p24{}).retryDelay(0); delay != 0 {
+		t.Errorf("retryDelay without a base = %v, want 0", delay)
+	}
+}
+
+func TestWithRetryPolicyOutOfRange(t *testing.T) {
+	tests := []struct {
+		max          int
+		wantAttempts int32
+	}{
+		{-1, 1},
+		{1, 2},
+	}
+
+	for _, tt := range tests {
+		var attempts atomic.Int32
+		handler := flakyHandler(&attempts, 10, []int{http.StatusServiceUnavailable}, registerHandler)
+		client := newTestClient(t, handler, Config{}, WithRetryPolicy(tt.max, -time.Second, nil))
+		if client.retryBase != 200*time.Millisecond {
+			t.Errorf("max %d: retryBase = %v, want the default", tt.max, client.retryBase)
+		}
+
+		if _, err := client.RegisterTransaction(testTransaction("order-1")); err == nil {
+			t.Errorf("max %d: got no error", tt.max)
+		}
+		if got := attempts.Load(); got != tt.wantAttempts {
+			t.Errorf("max %d: got %d attempts, want %d", tt.max, got, tt.wantAttempts)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package przelewy24
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// ReturnSessionIdParam is the query parameter BuildReturnURL stores the sessionId in.
+	ReturnSessionIdParam = "sessionId"
+	// ReturnSignParam is the query parameter SignedReturnURL stores the sign of the sessionId in.
+	ReturnSignParam = "sign"
+)
+
+// ReturnParams are the values read by ParseReturnURL from a request to urlReturn.
+type ReturnParams struct {
+	SessionId string
+	// Params are the other query parameters, e.g. tracking ones passed to BuildReturnURL.
+	Params url.Values
+}
+
+// BuildReturnURL returns returnURL with sessionId and params added to its query, to be
+// used as TransactionParams.UrlReturn. Przelewy24 sends the customer back to urlReturn
+// as it is, without adding anything about the payment, so the sessionId has to be
+// passed this way to tell which transaction the customer returns from.
+func BuildReturnURL(returnURL string, sessionId string, params url.Values) (string, error) {
+	u, err := url.Parse(returnURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid return url: %w", err)
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	query.Set(ReturnSessionIdParam, sessionId)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// ParseReturnURL reads the values added by BuildReturnURL from a request to urlReturn.
+// The customer can modify the url, so the status of the payment must still be taken
+// from a notification or GetTransactionBySessionId, not from the url.
+func ParseReturnURL(r *http.Request) ReturnParams {
+	query := r.URL.Query()
+	sessionId := query.Get(ReturnSessionIdParam)
+	query.Del(ReturnSessionIdParam)
+
+	return ReturnParams{
+		SessionId: sessionId,
+		Params:    query,
+	}
+}
+
+// SignedReturnURL is like BuildReturnURL, but also adds a sign of the sessionId calculated
+// with the configured crc, so that VerifyReturnURL can tell the sessionId was not modified
+// by the customer. Przelewy24 itself does not sign the return, so this sign is the
+// merchant's own. The other params are not signed.
+func (p24 *p24) SignedReturnURL(returnURL string, sessionId string, params url.Values) (string, error) {
+	if err := p24.checkCredentials(); err != nil {
+		return "", err
+	}
+
+	signed := url.Values{}
+	for key, values := range params {
+		signed[key] = values
+	}
+	signed.Set(ReturnSignParam, calculateReturnSignature(p24.signer, sessionId, p24.crc))
+
+	return BuildReturnURL(returnURL, sessionId, signed)
+}
+
+// VerifyReturnURL is like ParseReturnURL for a url built with SignedReturnURL, but
+// returns ErrInvalidSignature when the sign does not match the sessionId. Even then,
+// it only proves the customer returned from the given transaction, not that it was paid.
+func (p24 *p24) VerifyReturnURL(r *http.Request) (*ReturnParams, error) {
+	if err := p24.checkCredentials(); err != nil {
+		return nil, err
+	}
+
+	params := ParseReturnURL(r)
+	sign := params.Params.Get(ReturnSignParam)
+	params.Params.Del(ReturnSignParam)
+
+	if !signaturesEqual(calculateReturnSignature(p24.signer, params.SessionId, p24.crc), sign) {
+		return nil, ErrInvalidSignature
+	}
+
+	return &params, nil
+}
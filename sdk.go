@@ -0,0 +1,46 @@
+package przelewy24
+
+import "context"
+
+// SDKHandoff is what a front-end SDK of Przelewy24, such as the inline payment widget,
+// needs to take over a transaction registered on the server. It carries the sign of
+// the registration, never the crc it was calculated with, so it is safe to send to
+// the browser.
+type SDKHandoff struct {
+	Token      string   `json:"token"`
+	Sign       string   `json:"sign"`
+	MerchantId int      `json:"merchantId"`
+	PosId      int      `json:"posId"`
+	SessionId  string   `json:"sessionId"`
+	Amount     int      `json:"amount"`
+	Currency   Currency `json:"currency"`
+}
+
+// RegisterTransactionForSDK registers a transaction like RegisterTransactionResult, and
+// returns the token along with the sign of the registration for a front-end SDK.
+func (p24 *p24) RegisterTransactionForSDK(data TransactionParams, opts ...TransactionOption) (*SDKHandoff, error) {
+	return p24.RegisterTransactionForSDKContext(context.Background(), data, opts...)
+}
+
+// RegisterTransactionForSDKContext is like RegisterTransactionForSDK but the request is bound to ctx.
+func (p24 *p24) RegisterTransactionForSDKContext(ctx context.Context, data TransactionParams, opts ...TransactionOption) (*SDKHandoff, error) {
+	payload, err := p24.signTransaction(data, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p24.register(ctx, payload.PosId, payload.Amount, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SDKHandoff{
+		Token:      result.Token,
+		Sign:       payload.Sign,
+		MerchantId: payload.MerchantId,
+		PosId:      payload.PosId,
+		SessionId:  payload.SessionId,
+		Amount:     payload.Amount,
+		Currency:   payload.Currency,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package przelewy24
+
+import (
+	"context"
+	"sync"
+)
+
+// SeenStore remembers the notifications already processed by NotificationHandler, so
+// that one delivered more than once does not fulfill the order again, see Config.SeenStore.
+// Implement it on top of a database to keep the notifications across restarts and
+// instances of the application.
+type SeenStore interface {
+	// Claim records the notification of the transaction as processed unless it already
+	// was, and reports whether this call recorded it. It must be atomic, e.g. an insert
+	// into a table with a unique key, so that of concurrent deliveries only one claims it.
+	Claim(ctx context.Context, sessionId string, orderId int64) (bool, error)
+	// Release forgets a claimed notification whose processing failed, so that it is
+	// processed again when Przelewy24 resends it.
+	Release(ctx context.Context, sessionId string, orderId int64) error
+}
+
+// MemorySeenStore is a SeenStore keeping the processed notifications in memory.
+// It never forgets them, so it suits a single instance of an application that
+// is restarted from time to time.
+type MemorySeenStore struct {
+	mu   sync.Mutex
+	seen map[seenKey]struct{}
+}
+
+type seenKey struct {
+	sessionId string
+	orderId   int64
+}
+
+// NewMemorySeenStore returns an empty MemorySeenStore.
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{seen: make(map[seenKey]struct{})}
+}
+
+func (s *MemorySeenStore) Claim(ctx context.Context, sessionId string, orderId int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := seenKey{sessionId, orderId}
+	if _, ok := s.seen[key]; ok {
+		return false, nil
+	}
+	s.seen[key] = struct{}{}
+
+	return true, nil
+}
+
+func (s *MemorySeenStore) Release(ctx context.Context, sessionId string, orderId int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.seen, seenKey{sessionId, orderId})
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package przelewy24
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// MaxSessionIdLength is the longest sessionId accepted by the API.
+const MaxSessionIdLength = 100
+
+// NewSessionId returns a random, unique sessionId made of 32 hex characters.
+func NewSessionId() string {
+	b := make([]byte, 16)
+	// crypto/rand.Read never returns an error.
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,113 @@
+package przelewy24
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+)
+
+func calculateRegistrationSignature(signer Signer, sessionId string, merchantId int, amount int, currency Currency, crc string) string {
+	return calculateSignature(
+		signer,
+		signField{"sessionId", sessionId},
+		signField{"merchantId", merchantId},
+		signField{"amount", amount},
+		signField{"currency", currency},
+		signField{"crc", crc},
+	)
+}
+
+func calculateVerificationSignature(signer Signer, sessionId string, orderId int64, amount int, currency Currency, crc string) string {
+	return calculateSignature(
+		signer,
+		signField{"sessionId", sessionId},
+		signField{"orderId", orderId},
+		signField{"amount", amount},
+		signField{"currency", currency},
+		signField{"crc", crc},
+	)
+}
+
+func calculateNotificationSignature(signer Signer, merchantId int, posId int, sessionId string, amount int, originAmount int, currency Currency, orderId int64, methodId int, statement string, crc string) string {
+	return calculateSignature(
+		signer,
+		signField{"merchantId", merchantId},
+		signField{"posId", posId},
+		signField{"sessionId", sessionId},
+		signField{"amount", amount},
+		signField{"originAmount", originAmount},
+		signField{"currency", currency},
+		signField{"orderId", orderId},
+		signField{"methodId", methodId},
+		signField{"statement", statement},
+		signField{"crc", crc},
+	)
+}
+
+// calculateReturnSignature signs the sessionId passed to urlReturn by SignedReturnURL.
+func calculateReturnSignature(signer Signer, sessionId string, crc string) string {
+	return calculateSignature(
+		signer,
+		signField{"sessionId", sessionId},
+		signField{"crc", crc},
+	)
+}
+
+// signaturesEqual compares a calculated sign with a received one in constant time,
+// so the time taken does not reveal how much of the received sign is correct.
+func signaturesEqual(expected string, received string) bool {
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(received)) == 1
+}
+
+type signField struct {
+	key   string
+	value any
+}
+
+// calculateSignature returns the sign of fields made by signer. Fields are signed in the
+// given order, so a new signed parameter only has to be added to the list in the right place.
+func calculateSignature(signer Signer, fields ...signField) string {
+	return signer.Sign(encodeSignPayload(fields...))
+}
+
+// Signer turns the JSON payload of a sign, with the crc as its last field, into the
+// sign sent to and received from the API, see Config.Signer.
+type Signer interface {
+	Sign(payload []byte) string
+}
+
+// SHA384Signer is the Signer of the current API, the hex encoded SHA-384 hash of the payload.
+type SHA384Signer struct{}
+
+func (SHA384Signer) Sign(payload []byte) string {
+	hashSum := sha512.Sum384(payload)
+
+	return fmt.Sprintf("%x", hashSum)
+}
+
+// encodeSignPayload builds the JSON object hashed into a sign, keeping the order of fields.
+// Values are escaped the way P24 does it (json_encode with JSON_UNESCAPED_UNICODE and
+// JSON_UNESCAPED_SLASHES), so HTML characters and non-ASCII text are left as they are.
+func encodeSignPayload(fields ...signField) []byte {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		// Encoding a string or an integer cannot fail.
+		_ = encoder.Encode(field.key)
+		buf.Truncate(buf.Len() - 1)
+		buf.WriteByte(':')
+		_ = encoder.Encode(field.value)
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes()
+}
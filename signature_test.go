@@ -0,0 +1,228 @@
+package przelewy24
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyTransactionSignsPaidAmount(t *testing.T) {
+	var body map[string]any
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		w.Write([]byte(`{"data":{"status":"success"},"responseCode":0}`))
+	}, Config{MerchantId: 1000, Crc: "a1b2c3d4e5f6a7b8"})
+
+	// A partial payment: 6.00 PLN of 10.00 PLN.
+	_, err := client.VerifyTransaction(NotificationParams{
+		MerchantId:   1000,
+		PosId:        1000,
+		SessionId:    "order-1",
+		Amount:       600,
+		OriginAmount: 1000,
+		Currency:     CurrencyPLN,
+		OrderId:      312345678,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := body["originAmount"]; ok {
+		t.Errorf("verification body %v has originAmount", body)
+	}
+	// sha384 of {"sessionId":"order-1","orderId":312345678,"amount":600,"currency":"PLN","crc":"a1b2c3d4e5f6a7b8"}
+	want := "01de4ff36af4e4f33cbb5e6a1117fef995ff86167a9a66ea68fc004dabe8552e0414d32bdbe842afef70ed2f5e73f663"
+	if body["sign"] != want {
+		t.Errorf("sign = %v, want %s", body["sign"], want)
+	}
+}
+
+func TestSignaturesEqual(t *testing.T) {
+	sign := calculateReturnSignature(SHA384Signer{}, "order-1", "test-crc")
+	differing := []byte(sign)
+	differing[len(differing)/2] ^= 1
+
+	tests := []struct {
+		received string
+		want     bool
+	}{
+		{sign, true},
+		{string(differing), false},
+		{sign[:len(sign)-1], false},
+		{sign + "0", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := signaturesEqual(sign, tt.received); got != tt.want {
+			t.Errorf("signaturesEqual(%s, %s) = %t, want %t", sign, tt.received, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeSignPayload(t *testing.T) {
+	tests := []struct {
+		sessionId string
+		want      string
+	}{
+		{"order-1", `{"sessionId":"order-1","crc":"test-crc"}`},
+		{`order "1"`, `{"sessionId":"order \"1\"","crc":"test-crc"}`},
+		{`C:\orders\1`, `{"sessionId":"C:\\orders\\1","crc":"test-crc"}`},
+		{"zamówienie/1", `{"sessionId":"zamówienie/1","crc":"test-crc"}`},
+		{"<b>&</b>", `{"sessionId":"<b>&</b>","crc":"test-crc"}`},
+		{"zażółć gęślą jaźń €", `{"sessionId":"zażółć gęślą jaźń €","crc":"test-crc"}`},
+		{"line\nbreak\t", `{"sessionId":"line\nbreak\t","crc":"test-crc"}`},
+		// json_encode escapes the line terminators even with JSON_UNESCAPED_UNICODE.
+		{"a\u2028b", `{"sessionId":"a\u2028b","crc":"test-crc"}`},
+	}
+
+	for _, tt := range tests {
+		got := encodeSignPayload(signField{"sessionId", tt.sessionId}, signField{"crc", "test-crc"})
+		if string(got) != tt.want {
+			t.Errorf("sessionId %q: got %s, want %s", tt.sessionId, got, tt.want)
+		}
+	}
+}
+
+func TestRegistrationSignatureOfNonASCIISessionId(t *testing.T) {
+	// sha384 of {"sessionId":"zamówienie \"1\"/ą","merchantId":1000,"amount":1000,"currency":"PLN","crc":"test-crc"}
+	want := "b7b3df5d22cdacc68a9745661958c20816b46c58e97e54e0703e35bd2248c8499295d9ddef375cd01365700b346a7860"
+
+	if got := calculateRegistrationSignature(SHA384Signer{}, `zamówienie "1"/ą`, 1000, 1000, CurrencyPLN, "test-crc"); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestCalculateSignature(t *testing.T) {
+	const crc = "a1b2c3d4e5f6a7b8"
+	signer := SHA384Signer{}
+
+	// The wants are the sha384 of the payloads in the comments, built as documented by
+	// Przelewy24 and hashed independently of this package.
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		// {"sessionId":"order-1","merchantId":11111,"amount":1000,"currency":"PLN","crc":"a1b2c3d4e5f6a7b8"}
+		{
+			"registration",
+			calculateRegistrationSignature(signer, "order-1", 11111, 1000, CurrencyPLN, crc),
+			"77135306ca8d996f3b3bf10b64487d3a0002139904abb427c921ca4fff15ffa411fb18af7bc1f215c1748d51faf7137f",
+		},
+		// {"sessionId":"order-1","orderId":312345678,"amount":1000,"currency":"PLN","crc":"a1b2c3d4e5f6a7b8"}
+		{
+			"verification",
+			calculateVerificationSignature(signer, "order-1", 312345678, 1000, CurrencyPLN, crc),
+			"99ec6fa4422c23568527630a19289bc0f35c940776ef83e8dc917a618ccc4c66799332775ddaa906af2743501cc98268",
+		},
+		// {"merchantId":11111,"posId":11111,"sessionId":"order-1","amount":1000,"originAmount":1000,"currency":"PLN",
+		// "orderId":312345678,"methodId":25,"statement":"p24-A1-B2-C3","crc":"a1b2c3d4e5f6a7b8"}
+		{
+			"notification",
+			calculateNotificationSignature(signer, 11111, 11111, "order-1", 1000, 1000, CurrencyPLN, 312345678, 25, "p24-A1-B2-C3", crc),
+			"8a47713484e9659638fd0343f470c89d2ce58fb75b87cf4297a25b4aaf81ce6a84fbf2ec64ce92ce3661d34b934873b1",
+		},
+		// {"sessionId":"order-1","crc":"a1b2c3d4e5f6a7b8"}
+		{
+			"return",
+			calculateReturnSignature(signer, "order-1", crc),
+			"a4b410c85ecae53f4f63b7b1a24a88a871bb5d4c6085f9edf41047bf6b34b28aefc91956cd5a39092bd87984b05703cb",
+		},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s sign = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestSignsPerCurrency(t *testing.T) {
+	// The wants are the sha384 of the documented payloads with the merchantId, currency
+	// and crc of each case, e.g. {"sessionId":"order-1","merchantId":2000,"amount":1234,
+	// "currency":"EUR","crc":"crc-eur"} for the registration in EUR.
+	tests := []struct {
+		currency     Currency
+		merchantId   int
+		crc          string
+		registration string
+		verification string
+		notification string
+	}{
+		{
+			CurrencyPLN, 1000, "crc-pln",
+			"51e2fb09202efab9637cd99f622d9a288183aa896aaa8ce1ccd14c8813fe5417c5d5aa907ffcbf0efab03d79a9078af3",
+			"dd25512936dbd6be53a9c82195110e72d44a7e74d2e41287825ebe8268c6b4460a9edd69a6a6fa269bbdc654b978f960",
+			"1289c1887e52a484bc113bda995eb4b4468b06fee5a43819f6c9a864d3bd52587becfc8e36ce2785447a56cefa755826",
+		},
+		{
+			CurrencyEUR, 2000, "crc-eur",
+			"034c22f9da2aff2ce598ec1a6cf9cd9985a02d0c52a6979f840f99781a1795f71d04949c902c8d3e0b20f8b33f9205a9",
+			"7fa8eb9f962d07988f1f76fc027fab21af893c875f759d61e4274f76c5243453ebefc6ce41303991259ae7dd4654c872",
+			"8340605b9c36c1e2919d06941336d05f1a76c3164a99a108f9ae28c2aaadd9f64207a8701f0a315ae0de8654c7b376fd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.currency), func(t *testing.T) {
+			signs := map[string]any{}
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Error(err)
+				}
+				if body["currency"] != string(tt.currency) {
+					t.Errorf("%s sent with currency %v", r.URL.Path, body["currency"])
+				}
+				signs[r.URL.Path] = body["sign"]
+
+				if r.URL.Path == "/api/v1/transaction/verify" {
+					w.Write([]byte(`{"data":{"status":"success"},"responseCode":0}`))
+					return
+				}
+				registerHandler(w, r)
+			}, Config{MerchantId: tt.merchantId, Crc: tt.crc})
+
+			data := testTransaction("order-1")
+			data.Amount, data.Currency = 1234, tt.currency
+			if _, err := client.RegisterTransaction(data); err != nil {
+				t.Fatal(err)
+			}
+
+			notification := NotificationParams{
+				MerchantId:   tt.merchantId,
+				PosId:        tt.merchantId,
+				SessionId:    "order-1",
+				Amount:       1234,
+				OriginAmount: 1234,
+				Currency:     tt.currency,
+				OrderId:      4321,
+				MethodId:     25,
+				Statement:    "p24-4321",
+				Sign:         tt.notification,
+			}
+			if !client.VerifyNotificationSignature(notification) {
+				t.Error("notification sign rejected")
+			}
+			if _, err := client.VerifyTransaction(notification); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := signs["/api/v1/transaction/register"]; got != tt.registration {
+				t.Errorf("registration sign = %v, want %s", got, tt.registration)
+			}
+			if got := signs["/api/v1/transaction/verify"]; got != tt.verification {
+				t.Errorf("verification sign = %v, want %s", got, tt.verification)
+			}
+
+			// The currency is signed, so a notification cannot be replayed in another one.
+			notification.Currency = map[Currency]Currency{CurrencyPLN: CurrencyEUR, CurrencyEUR: CurrencyPLN}[tt.currency]
+			if client.VerifyNotificationSignature(notification) {
+				t.Error("notification sign accepted in another currency")
+			}
+		})
+	}
+}
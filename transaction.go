@@ -0,0 +1,256 @@
+package przelewy24
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type TransactionInfo struct {
+	Statement         string            `json:"statement"`
+	OrderId           int64             `json:"orderId"`
+	SessionId         string            `json:"sessionId"`
+	Status            TransactionStatus `json:"status"`
+	Amount            int               `json:"amount"`
+	Currency          Currency          `json:"currency"`
+	Date              string            `json:"date"`
+	DateOfTransaction string            `json:"dateOfTransaction"`
+	ClientEmail       string            `json:"clientEmail"`
+	AccountMD5        string            `json:"accountMD5"`
+	PaymentMethod     int               `json:"paymentMethod"`
+	Description       string            `json:"description"`
+	ClientName        string            `json:"clientName"`
+	ClientAddress     string            `json:"clientAddress"`
+	ClientCity        string            `json:"clientCity"`
+	ClientPostcode    string            `json:"clientPostcode"`
+	BatchId           int               `json:"batchId"`
+	Fee               string            `json:"fee"`
+}
+
+// TransactionStatus is the state of a transaction, see TransactionInfo.
+type TransactionStatus int
+
+const (
+	// TransactionStatusUnknown stands for a status not known to this library.
+	TransactionStatusUnknown   TransactionStatus = -1
+	TransactionStatusNoPayment TransactionStatus = 0
+	TransactionStatusAdvance   TransactionStatus = 1
+	TransactionStatusPaid      TransactionStatus = 2
+	TransactionStatusReturned  TransactionStatus = 3
+)
+
+func (s TransactionStatus) String() string {
+	switch s {
+	case TransactionStatusNoPayment:
+		return "no payment"
+	case TransactionStatusAdvance:
+		return "advance payment"
+	case TransactionStatusPaid:
+		return "paid"
+	case TransactionStatusReturned:
+		return "returned"
+	default:
+		return "unknown"
+	}
+}
+
+// UnmarshalJSON maps the status codes of the API, decoding unexpected ones as TransactionStatusUnknown.
+func (s *TransactionStatus) UnmarshalJSON(data []byte) error {
+	var code int
+	if err := json.Unmarshal(data, &code); err != nil {
+		return err
+	}
+
+	switch status := TransactionStatus(code); status {
+	case TransactionStatusNoPayment, TransactionStatusAdvance, TransactionStatusPaid, TransactionStatusReturned:
+		*s = status
+	default:
+		*s = TransactionStatusUnknown
+	}
+
+	return nil
+}
+
+type TransactionInfoResponse struct {
+	Data TransactionInfo `json:"data"`
+	BaseResponse
+}
+
+// GetTransactionBySessionId returns the transaction registered with sessionId.
+func (p24 *p24) GetTransactionBySessionId(sessionId string) (*TransactionInfo, error) {
+	return p24.GetTransactionBySessionIdContext(context.Background(), sessionId)
+}
+
+// GetTransactionBySessionIdContext is like GetTransactionBySessionId but the request is bound to ctx.
+func (p24 *p24) GetTransactionBySessionIdContext(ctx context.Context, sessionId string) (*TransactionInfo, error) {
+	return p24.getTransaction(ctx, p24.baseURL+"/api/v1/transaction/by/sessionId/"+url.PathEscape(sessionId))
+}
+
+// GetTransactionByOrderId returns the transaction with the orderId assigned by Przelewy24.
+func (p24 *p24) GetTransactionByOrderId(orderId int64) (*TransactionInfo, error) {
+	return p24.GetTransactionByOrderIdContext(context.Background(), orderId)
+}
+
+// GetTransactionByOrderIdContext is like GetTransactionByOrderId but the request is bound to ctx.
+func (p24 *p24) GetTransactionByOrderIdContext(ctx context.Context, orderId int64) (*TransactionInfo, error) {
+	return p24.getTransaction(ctx, p24.baseURL+"/api/v1/transaction/by/orderId/"+strconv.FormatInt(orderId, 10))
+}
+
+// getTransaction fetches a transaction from endpoint. When there is no such transaction,
+// the returned error matches ErrTransactionNotFound.
+func (p24 *p24) getTransaction(ctx context.Context, endpoint string) (*TransactionInfo, error) {
+	var respBody TransactionInfoResponse
+	resp, err := p24.sendIdempotentRequest(ctx, "GET", endpoint, nil, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		apiErr := resp.apiError(respBody.BaseResponse)
+		if resp.StatusCode == 404 {
+			apiErr.Err = ErrTransactionNotFound
+		}
+
+		return nil, apiErr
+	}
+
+	return &respBody.Data, nil
+}
+
+// TransactionFilter narrows down the transactions returned by SearchTransactions.
+// Zero values are not sent.
+type TransactionFilter struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	Currency Currency
+	Status   *TransactionStatus
+
+	// Page is numbered from 1. Limit is the number of transactions on a page.
+	Page  int
+	Limit int
+}
+
+// TransactionPage is a single page of the results of SearchTransactions.
+type TransactionPage struct {
+	Transactions []TransactionInfo
+	Page         int
+	Limit        int
+	Total        int
+}
+
+// HasMore reports whether there are further pages after this one.
+func (p *TransactionPage) HasMore() bool {
+	return p.Page*p.Limit < p.Total
+}
+
+type TransactionSearchResponse struct {
+	Data  []TransactionInfo `json:"data"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+	Total int               `json:"total"`
+	BaseResponse
+}
+
+// SearchTransactions returns a page of the transactions matching filter, e.g. for daily reconciliation.
+func (p24 *p24) SearchTransactions(filter TransactionFilter) (*TransactionPage, error) {
+	return p24.SearchTransactionsContext(context.Background(), filter)
+}
+
+// SearchTransactionsContext is like SearchTransactions but the request is bound to ctx.
+func (p24 *p24) SearchTransactionsContext(ctx context.Context, filter TransactionFilter) (*TransactionPage, error) {
+	query := url.Values{}
+	if !filter.DateFrom.IsZero() {
+		query.Set("dateFrom", filter.DateFrom.Format(time.DateOnly))
+	}
+	if !filter.DateTo.IsZero() {
+		query.Set("dateTo", filter.DateTo.Format(time.DateOnly))
+	}
+	if filter.Currency != "" {
+		query.Set("currency", string(filter.Currency))
+	}
+	if filter.Status != nil {
+		query.Set("status", strconv.Itoa(int(*filter.Status)))
+	}
+	if filter.Page > 0 {
+		query.Set("page", strconv.Itoa(filter.Page))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	endpoint := p24.baseURL + "/api/v1/transaction/search"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var respBody TransactionSearchResponse
+	resp, err := p24.sendIdempotentRequest(ctx, "GET", endpoint, nil, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, resp.apiError(respBody.BaseResponse)
+	}
+
+	page := &TransactionPage{
+		Transactions: respBody.Data,
+		Page:         respBody.Page,
+		Limit:        respBody.Limit,
+		Total:        respBody.Total,
+	}
+	if page.Page == 0 {
+		page.Page = max(filter.Page, 1)
+	}
+	if page.Limit == 0 {
+		page.Limit = max(filter.Limit, len(page.Transactions))
+	}
+
+	return page, nil
+}
+
+// SearchTransactionPages returns an iterator over the pages of the transactions matching
+// filter, starting from filter.Page. Pages are fetched one at a time, as the iteration
+// proceeds. The iteration stops after the first error.
+func (p24 *p24) SearchTransactionPages(ctx context.Context, filter TransactionFilter) iter.Seq2[*TransactionPage, error] {
+	return func(yield func(*TransactionPage, error) bool) {
+		filter.Page = max(filter.Page, 1)
+
+		for {
+			page, err := p24.SearchTransactionsContext(ctx, filter)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yield(page, nil) || !page.HasMore() || len(page.Transactions) == 0 {
+				return
+			}
+
+			filter.Page = page.Page + 1
+			filter.Limit = page.Limit
+		}
+	}
+}
+
+// SearchAllTransactions is like SearchTransactions, but fetches all the pages of
+// results. See SearchTransactionPages for processing them page by page instead.
+func (p24 *p24) SearchAllTransactions(filter TransactionFilter) ([]TransactionInfo, error) {
+	return p24.SearchAllTransactionsContext(context.Background(), filter)
+}
+
+// SearchAllTransactionsContext is like SearchAllTransactions but the requests are bound to ctx.
+func (p24 *p24) SearchAllTransactionsContext(ctx context.Context, filter TransactionFilter) ([]TransactionInfo, error) {
+	var transactions []TransactionInfo
+	for page, err := range p24.SearchTransactionPages(ctx, filter) {
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, page.Transactions...)
+	}
+
+	return transactions, nil
+}
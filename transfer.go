@@ -0,0 +1,77 @@
+package przelewy24
+
+import (
+	"context"
+	"strings"
+)
+
+// manualTransferGroup is the group of the manual bank transfer methods in the
+// English list of payment methods.
+const manualTransferGroup = "Traditional transfer"
+
+// IsManualTransfer reports whether m is a manual bank transfer, for which the customer
+// makes the transfer themselves instead of being redirected to their bank. m must come
+// from PaymentMethods called with "en", as the group is compared by name.
+func (m PaymentMethod) IsManualTransfer() bool {
+	return strings.EqualFold(m.Group, manualTransferGroup)
+}
+
+// TransferInstructions is what a customer paying by a manual bank transfer needs to
+// make the transfer. The API does not return the account of the recipient; it is
+// shown on the payment page and in the email sent by Przelewy24.
+type TransferInstructions struct {
+	// Title must be used as the title of the transfer, otherwise Przelewy24 cannot
+	// match the transfer with the transaction.
+	Title     string
+	Amount    int
+	Currency  Currency
+	OrderId   int64
+	SessionId string
+	Method    PaymentMethod
+}
+
+// TransferInstructions returns the details of the manual bank transfer the customer
+// chose to pay the transaction registered with sessionId by. The API has no lookup by
+// token, so the transaction is found by its sessionId. When the customer has not
+// chosen a manual transfer, the returned error is ErrNotManualTransfer.
+func (p24 *p24) TransferInstructions(sessionId string) (*TransferInstructions, error) {
+	return p24.TransferInstructionsContext(context.Background(), sessionId)
+}
+
+// TransferInstructionsContext is like TransferInstructions but the requests are bound to ctx.
+func (p24 *p24) TransferInstructionsContext(ctx context.Context, sessionId string) (*TransferInstructions, error) {
+	transaction, err := p24.GetTransactionBySessionIdContext(ctx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// No method is recorded until the customer picks one on the payment page.
+	if transaction.PaymentMethod == 0 {
+		return nil, ErrNotManualTransfer
+	}
+
+	methods, err := p24.PaymentMethodsContext(ctx, "en")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, method := range methods {
+		if method.Id != transaction.PaymentMethod {
+			continue
+		}
+		if !method.IsManualTransfer() {
+			break
+		}
+
+		return &TransferInstructions{
+			Title:     transaction.Statement,
+			Amount:    transaction.Amount,
+			Currency:  transaction.Currency,
+			OrderId:   transaction.OrderId,
+			SessionId: transaction.SessionId,
+			Method:    method,
+		}, nil
+	}
+
+	return nil, ErrNotManualTransfer
+}
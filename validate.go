@@ -0,0 +1,152 @@
+package przelewy24
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxAmount is the largest amount, in minor units, that fits the amount field of the API.
+const MaxAmount = math.MaxInt32
+
+// MaxTimeLimit is the longest TimeLimit, in minutes, accepted by the API.
+const MaxTimeLimit = 99
+
+// MaxDescriptionLength is the longest Description, in characters, accepted by the API.
+const MaxDescriptionLength = 1024
+
+// ValidationError is returned when a request is rejected before being sent to the API.
+type ValidationError struct {
+	Field   string
+	Message string
+	// Err is a sentinel error describing the problem, if any.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateTransactionParams checks data for problems that would make the API
+// reject the registration. It is called by RegisterTransaction, but can also be
+// used on its own, e.g. to validate a checkout form.
+func ValidateTransactionParams(data TransactionParams) error {
+	if err := validateSessionIdAndAmount(data.SessionId, data.Amount); err != nil {
+		return err
+	}
+
+	return validateInvariantParams(data)
+}
+
+// validateSessionIdAndAmount checks the params that vary between transactions prepared
+// with PrepareTransaction.
+func validateSessionIdAndAmount(sessionId string, amount int) error {
+	if sessionId == "" {
+		return &ValidationError{Field: "sessionId", Message: "must not be empty"}
+	}
+	if len(sessionId) > MaxSessionIdLength {
+		return &ValidationError{Field: "sessionId", Message: fmt.Sprintf("must be at most %d characters long", MaxSessionIdLength)}
+	}
+
+	if amount <= 0 || amount > MaxAmount {
+		return &ValidationError{Field: "amount", Message: fmt.Sprintf("must be between 1 and %d, got %d", MaxAmount, amount), Err: ErrInvalidAmount}
+	}
+
+	return nil
+}
+
+// validateInvariantParams checks all the params of data but the sessionId and amount.
+func validateInvariantParams(data TransactionParams) error {
+	if !data.Currency.Valid() {
+		return &ValidationError{Field: "currency", Message: fmt.Sprintf("%q is not supported", data.Currency)}
+	}
+
+	if data.Email == "" {
+		return &ValidationError{Field: "email", Message: "must not be empty", Err: ErrInvalidEmail}
+	}
+	if !validEmail(data.Email) {
+		return &ValidationError{Field: "email", Message: fmt.Sprintf("%q is not an email address", data.Email), Err: ErrInvalidEmail}
+	}
+
+	if err := validateDescription(data.Description); err != nil {
+		return err
+	}
+
+	if data.Country != "" && !data.Country.Valid() {
+		return &ValidationError{Field: "country", Message: fmt.Sprintf("%q is not supported", data.Country)}
+	}
+
+	if data.Language != "" && !data.Language.Valid() {
+		return &ValidationError{Field: "language", Message: fmt.Sprintf("%q is not supported", data.Language)}
+	}
+
+	if data.TimeLimit < 0 || data.TimeLimit > MaxTimeLimit {
+		return &ValidationError{Field: "timeLimit", Message: fmt.Sprintf("must be between 0 and %d minutes, got %d", MaxTimeLimit, data.TimeLimit)}
+	}
+
+	if data.Channel != 0 && !data.Channel.Valid() {
+		return &ValidationError{Field: "channel", Message: fmt.Sprintf("%d is not a combination of the documented channels", data.Channel)}
+	}
+
+	return nil
+}
+
+// validEmail reports whether email is a bare address, without a display name or
+// surrounding spaces, with a dot in its domain as the API expects.
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return false
+	}
+
+	domain := addr.Address[strings.LastIndex(addr.Address, "@")+1:]
+	return strings.Contains(strings.Trim(domain, "."), ".")
+}
+
+// NormalizeEmail trims spaces around email and lowercases it, see WithNormalizedEmail.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// validateDescription rejects descriptions the API would reject: overlong ones and
+// ones that are not valid UTF-8 or contain control characters.
+func validateDescription(description string) error {
+	if !utf8.ValidString(description) {
+		return &ValidationError{Field: "description", Message: "must be valid UTF-8"}
+	}
+	if n := utf8.RuneCountInString(description); n > MaxDescriptionLength {
+		return &ValidationError{Field: "description", Message: fmt.Sprintf("must be at most %d characters long, got %d", MaxDescriptionLength, n)}
+	}
+	if i := strings.IndexFunc(description, unicode.IsControl); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(description[i:])
+		return &ValidationError{Field: "description", Message: fmt.Sprintf("must not contain control characters, got %q at byte %d", r, i)}
+	}
+
+	return nil
+}
+
+// SanitizeDescription makes description acceptable for TransactionParams.Description:
+// invalid UTF-8 is dropped, control characters such as newlines are replaced with
+// spaces and the result is cut to MaxDescriptionLength characters.
+func SanitizeDescription(description string) string {
+	description = strings.ToValidUTF8(description, "")
+	description = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, description)
+
+	if utf8.RuneCountInString(description) > MaxDescriptionLength {
+		description = string([]rune(description)[:MaxDescriptionLength])
+	}
+
+	return description
+}